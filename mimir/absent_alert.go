@@ -0,0 +1,259 @@
+package mimir
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// absentAlertMetricLabel is the label holding the metric name of the
+// generated absent alerts.
+const absentAlertMetricLabel = "metric"
+
+func absentAlertSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Generate an alert firing when a series selected by the rules of the group is absent, one per selector.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"alert": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "MetricAbsent",
+					Description:  "The name of the generated alerts.",
+					ValidateFunc: validateAlertingRuleName,
+				},
+				"for": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Description:  "The duration for which the series must be absent before the alerts fire.",
+					ValidateFunc: validateDuration,
+				},
+				"range": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Description:  "When set, use `absent_over_time()` over this range instead of `absent()`.",
+					ValidateFunc: validateDuration,
+				},
+				"labels": {
+					Type:         schema.TypeMap,
+					Optional:     true,
+					Description:  "Labels to add to the generated alerts. The `metric` label is set to the metric name of the selector.",
+					Elem:         &schema.Schema{Type: schema.TypeString},
+					ValidateFunc: validateLabels,
+				},
+				"annotations": {
+					Type:         schema.TypeMap,
+					Optional:     true,
+					Description:  "Annotations to add to the generated alerts.",
+					Elem:         &schema.Schema{Type: schema.TypeString},
+					ValidateFunc: validateAnnotations,
+				},
+				"exclude": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Description: "Regexes of the metric names to not generate alerts for. They are anchored on both ends.",
+					Elem: &schema.Schema{
+						Type:         schema.TypeString,
+						ValidateFunc: validateAbsentAlertExclude,
+					},
+				},
+			},
+		},
+	}
+}
+
+// absentRuleSchema is the schema of the generated absent alerts.
+func absentRuleSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "Alerts generated by `absent_alert`.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"alert": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"expr": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"for": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"labels": {
+					Type:     schema.TypeMap,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+					Computed: true,
+				},
+				"annotations": {
+					Type:     schema.TypeMap,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+					Computed: true,
+				},
+			},
+		},
+	}
+}
+
+type absentAlert struct {
+	Alert       string
+	For         string
+	Range       string
+	Labels      map[string]string
+	Annotations map[string]string
+	Exclude     []*regexp.Regexp
+}
+
+func expandAbsentAlert(v []interface{}) (*absentAlert, error) {
+	if len(v) == 0 || v[0] == nil {
+		return nil, nil
+	}
+	data := v[0].(map[string]interface{})
+
+	alert := &absentAlert{
+		Alert: data["alert"].(string),
+		For:   data["for"].(string),
+		Range: data["range"].(string),
+	}
+	if m := data["labels"].(map[string]interface{}); len(m) > 0 {
+		alert.Labels = expandStringMap(m)
+	}
+	if m := data["annotations"].(map[string]interface{}); len(m) > 0 {
+		alert.Annotations = expandStringMap(m)
+	}
+	for _, raw := range expandStringArray(data["exclude"].([]interface{})) {
+		re, err := regexp.Compile("^(?:" + raw + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("Invalid exclude regex %q: %v", raw, err)
+		}
+		alert.Exclude = append(alert.Exclude, re)
+	}
+
+	return alert, nil
+}
+
+// generateAbsentAlertingRules returns an absent alert for every distinct
+// selector of the expressions, except the selectors already wrapped in
+// absent() and the excluded metrics.
+func generateAbsentAlertingRules(alert *absentAlert, exprs []string) ([]alertingRule, error) {
+	if alert == nil {
+		return nil, nil
+	}
+
+	selectors := make(map[string]string)
+	for _, expr := range exprs {
+		node, err := parser.ParseExpr(expr)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid PromQL expression %q: %v", expr, err)
+		}
+
+		parser.Inspect(node, func(n parser.Node, path []parser.Node) error {
+			vs, ok := n.(*parser.VectorSelector)
+			if !ok {
+				return nil
+			}
+			for _, p := range path {
+				if call, ok := p.(*parser.Call); ok && strings.HasPrefix(call.Func.Name, "absent") {
+					return nil
+				}
+			}
+
+			name := vs.Name
+			for _, m := range vs.LabelMatchers {
+				if m.Name == labels.MetricName && m.Type == labels.MatchEqual {
+					name = m.Value
+				}
+			}
+			for _, re := range alert.Exclude {
+				if re.MatchString(name) {
+					return nil
+				}
+			}
+
+			// Drop the offset and @ modifiers, only the series matter.
+			selector := &parser.VectorSelector{Name: vs.Name, LabelMatchers: vs.LabelMatchers}
+			selectors[selector.String()] = name
+			return nil
+		})
+	}
+
+	keys := make([]string, 0, len(selectors))
+	for selector := range selectors {
+		keys = append(keys, selector)
+	}
+	sort.Strings(keys)
+
+	var rules []alertingRule
+	for _, selector := range keys {
+		rule := alertingRule{
+			Alert:       alert.Alert,
+			Expr:        fmt.Sprintf("absent(%s)", selector),
+			For:         alert.For,
+			Annotations: alert.Annotations,
+		}
+		if alert.Range != "" {
+			rule.Expr = fmt.Sprintf("absent_over_time(%s[%s])", selector, alert.Range)
+		}
+
+		if len(alert.Labels) > 0 || selectors[selector] != "" {
+			rule.Labels = make(map[string]string)
+			for key, val := range alert.Labels {
+				rule.Labels[key] = val
+			}
+			if selectors[selector] != "" {
+				rule.Labels[absentAlertMetricLabel] = selectors[selector]
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// splitAbsentAlertingRules splits the rules read from the ruler into the
+// configured rules and the trailing generated absent alerts, which are as many
+// as the alerts generated from the configured rules.
+func splitAbsentAlertingRules(alert *absentAlert, rules []alertingRule) ([]alertingRule, []alertingRule) {
+	if alert == nil {
+		return rules, nil
+	}
+
+	start := len(rules)
+	for start > 0 && rules[start-1].Alert == alert.Alert && strings.HasPrefix(rules[start-1].Expr, "absent") {
+		start--
+	}
+
+	for i := start; i <= len(rules); i++ {
+		var exprs []string
+		for _, rule := range rules[:i] {
+			exprs = append(exprs, rule.Expr)
+		}
+		generated, err := generateAbsentAlertingRules(alert, exprs)
+		if err == nil && len(generated) == len(rules)-i {
+			return rules[:i], rules[i:]
+		}
+	}
+
+	return rules, nil
+}
+
+func validateAbsentAlertExclude(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if _, err := regexp.Compile(value); err != nil {
+		errors = append(errors, fmt.Errorf("\"%s\": Invalid regex %q: %v", k, value, err))
+	}
+
+	return
+}
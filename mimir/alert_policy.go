@@ -0,0 +1,188 @@
+package mimir
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	alertPolicyLevelError   = "error"
+	alertPolicyLevelWarning = "warning"
+)
+
+func alertPolicySchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Policy checked on the labels and annotations of every alerting rule, including the default ones.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"level": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      alertPolicyLevelError,
+					Description:  "Whether the violations are errors failing the plan (`error`) or warnings (`warning`).",
+					ValidateFunc: validation.StringInSlice([]string{alertPolicyLevelError, alertPolicyLevelWarning}, false),
+				},
+				"required_labels": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Description: "Labels every alert must have.",
+					Elem:        &schema.Schema{Type: schema.TypeString},
+				},
+				"required_annotations": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Description: "Annotations every alert must have.",
+					Elem:        &schema.Schema{Type: schema.TypeString},
+				},
+				"allowed_values": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Description: "Values allowed for a label.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"label": {
+								Type:         schema.TypeString,
+								Required:     true,
+								Description:  "Label name.",
+								ValidateFunc: validation.StringMatch(labelNameRegexp, "must be a valid label name"),
+							},
+							"values": {
+								Type:        schema.TypeList,
+								Required:    true,
+								Description: "Values allowed for the label.",
+								Elem:        &schema.Schema{Type: schema.TypeString},
+							},
+						},
+					},
+				},
+				"url_annotations": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Description: "Annotations which, when set, must be absolute http or https URLs.",
+					Elem:        &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		},
+	}
+}
+
+type alertPolicy struct {
+	Level               string
+	RequiredLabels      []string
+	RequiredAnnotations []string
+	AllowedValues       map[string][]string
+	URLAnnotations      []string
+}
+
+func expandAlertPolicy(v []interface{}) *alertPolicy {
+	if len(v) == 0 || v[0] == nil {
+		return nil
+	}
+	data := v[0].(map[string]interface{})
+
+	policy := &alertPolicy{
+		Level:               data["level"].(string),
+		RequiredLabels:      expandStringArray(data["required_labels"].([]interface{})),
+		RequiredAnnotations: expandStringArray(data["required_annotations"].([]interface{})),
+		AllowedValues:       make(map[string][]string),
+		URLAnnotations:      expandStringArray(data["url_annotations"].([]interface{})),
+	}
+	for _, raw := range data["allowed_values"].([]interface{}) {
+		allowed := raw.(map[string]interface{})
+		label := allowed["label"].(string)
+		policy.AllowedValues[label] = append(policy.AllowedValues[label], expandStringArray(allowed["values"].([]interface{}))...)
+	}
+
+	return policy
+}
+
+// checkAlertPolicy returns the policy violations of the alerting rules of a
+// group.
+func checkAlertPolicy(policy *alertPolicy, namespace, group string, rules []alertingRule) []string {
+	var violations []string
+
+	if policy == nil {
+		return violations
+	}
+
+	for _, rule := range rules {
+		prefix := fmt.Sprintf("alert %q of rule group '%s/%s'", rule.Alert, namespace, group)
+
+		for _, name := range policy.RequiredLabels {
+			if _, ok := rule.Labels[name]; !ok {
+				violations = append(violations, fmt.Sprintf("%s: missing required label %q", prefix, name))
+			}
+		}
+		for _, name := range policy.RequiredAnnotations {
+			if _, ok := rule.Annotations[name]; !ok {
+				violations = append(violations, fmt.Sprintf("%s: missing required annotation %q", prefix, name))
+			}
+		}
+
+		labels := make([]string, 0, len(policy.AllowedValues))
+		for name := range policy.AllowedValues {
+			labels = append(labels, name)
+		}
+		sort.Strings(labels)
+		for _, name := range labels {
+			value, ok := rule.Labels[name]
+			if !ok || SliceFind(policy.AllowedValues[name], value) {
+				continue
+			}
+			violations = append(violations, fmt.Sprintf("%s: label %q value %q is not one of %q", prefix, name, value, policy.AllowedValues[name]))
+		}
+
+		for _, name := range policy.URLAnnotations {
+			value, ok := rule.Annotations[name]
+			if !ok {
+				continue
+			}
+			if u, err := url.Parse(value); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				violations = append(violations, fmt.Sprintf("%s: annotation %q value %q is not an absolute http or https URL", prefix, name, value))
+			}
+		}
+	}
+
+	return violations
+}
+
+// alertPolicyError returns the error failing the plan when the policy level
+// is error.
+func alertPolicyError(policy *alertPolicy, violations []string) error {
+	if policy == nil || policy.Level != alertPolicyLevelError || len(violations) == 0 {
+		return nil
+	}
+
+	msg := "Alert policy violations:"
+	for _, v := range violations {
+		msg += "\n  - " + v
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+// alertPolicyWarnings returns the warnings of the violations when the policy
+// level is warning.
+func alertPolicyWarnings(policy *alertPolicy, violations []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if policy == nil || policy.Level != alertPolicyLevelWarning {
+		return diags
+	}
+
+	for _, v := range violations {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Alert policy violation",
+			Detail:   v,
+		})
+	}
+	return diags
+}
@@ -4,16 +4,25 @@ package mimir
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"math/rand"
 	"net/http"
 	"net/http/httputil"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	xoauth2 "golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 )
 
 type apiClientOpt struct {
@@ -30,30 +39,120 @@ type apiClientOpt struct {
 	headers          map[string]string
 	timeout          int
 	debug            bool
+	// Settings of the ruler and alertmanager endpoints, overriding the
+	// ones above
+	ruler        *apiEndpointOpt
+	alertmanager *apiEndpointOpt
+	// OAuth2 client credentials used instead of the token and basic auth
+	oauth2 *apiOAuth2Opt
+	// Requests in flight and requests per second limits, unlimited when 0
+	max_concurrent_requests int
+	requests_per_second     float64
+	// Retries of the failed requests and wait bounds between them
+	retries        int
+	retry_wait_min time.Duration
+	retry_wait_max time.Duration
+	// Label matchers enforced on the rule expressions
+	enforced_matchers map[string]string
+	// Labels and annotations merged into the alerting rules
+	default_alert_labels      map[string]string
+	default_alert_annotations map[string]string
+	// Policy checked on the alerting rules
+	alert_policy *alertPolicy
+	// Level of the rule dependencies check, disabled when empty
+	rule_dependencies_check string
+	// Level of the duplicate rules check, disabled when empty
+	duplicate_rules_check string
+}
+
+// apiEndpointOpt holds the settings of a component endpoint. The unset
+// settings fall back to the top-level ones and the headers are merged.
+type apiEndpointOpt struct {
+	uri      string
+	cert     string
+	key      string
+	ca       string
+	token    string
+	insecure *bool
+	username string
+	password string
+	headers  map[string]string
+	timeout  int
+}
+
+// apiOAuth2Opt holds the OAuth2 client credentials and the TLS settings of
+// the token endpoint.
+type apiOAuth2Opt struct {
+	token_url       string
+	client_id       string
+	client_secret   string
+	scopes          []string
+	endpoint_params map[string]string
+	cert            string
+	key             string
+	ca              string
+	insecure        bool
+}
+
+// apiEndpoint is the endpoint of a component with its own HTTP client and
+// auth.
+type apiEndpoint struct {
+	http_client *http.Client
+	uri         string
+	token       string
+	username    string
+	password    string
+	headers     map[string]string
 }
 
 type api_client struct {
-	http_client      *http.Client
-	uri              string
-	ruler_uri        string
-	alertmanager_uri string
-	cert             string
-	key              string
-	ca               string
-	insecure         bool
-	token            string
-	username         string
-	password         string
-	headers          map[string]string
-	timeout          int
-	debug            bool
+	// Default URI, used in the error messages
+	uri     string
+	headers map[string]string
+	debug   bool
+	// Endpoints by component, the empty one being the default endpoint.
+	// They and the headers are not modified after the configuration, as
+	// the resources send requests concurrently.
+	endpoints map[string]*apiEndpoint
+	limiter   *requestLimiter
+	// Retries of the failed requests and wait bounds between them
+	retries        int
+	retry_wait_min time.Duration
+	retry_wait_max time.Duration
+	// Label matchers enforced on the rule expressions
+	enforced_matchers map[string]string
+	// Labels and annotations merged into the alerting rules
+	default_alert_labels      map[string]string
+	default_alert_annotations map[string]string
+	// Policy checked on the alerting rules
+	alert_policy *alertPolicy
+	// Level of the rule dependencies check, disabled when empty
+	rule_dependencies_check string
+	// Level of the duplicate rules check, disabled when empty
+	duplicate_rules_check string
+	// Rule groups of the tenant read by the checks above
+	rule_groups *ruleGroupsCache
 }
 
 // Make a new api client for RESTful calls
 func NewAPIClient(opt *apiClientOpt) (*api_client, error) {
+	ruler := opt.ruler
+	if ruler == nil {
+		ruler = &apiEndpointOpt{}
+	}
+	alertmanager := opt.alertmanager
+	if alertmanager == nil {
+		alertmanager = &apiEndpointOpt{}
+	}
+	if ruler.uri == "" {
+		ruler.uri = opt.ruler_uri
+	}
+	if alertmanager.uri == "" {
+		alertmanager.uri = opt.alertmanager_uri
+	}
 
-	if opt.uri == "" && opt.ruler_uri == "" && opt.alertmanager_uri == "" {
-		return nil, errors.New("No provider URIs defined. Please set uri, or ruler_rui/alertmanager_uri.")
+	if opt.uri == "" && ruler.uri == "" && alertmanager.uri == "" {
+		return nil, errors.New("No provider URIs defined. Please set uri, or ruler_uri/alertmanager_uri.")
 	}
 
 	/* Remove any trailing slashes since we will append
@@ -62,33 +161,234 @@ func NewAPIClient(opt *apiClientOpt) (*api_client, error) {
 		opt.uri = opt.uri[:len(opt.uri)-1]
 	}
 
+	var tokenSource *apiTokenSource
+	if opt.oauth2 != nil {
+		var err error
+		tokenSource, err = newOAuth2TokenSource(opt.oauth2, opt.timeout)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid oauth2 settings: %v", err)
+		}
+	}
+
+	endpoints := make(map[string]*apiEndpoint)
+	for component, v := range map[string]*apiEndpointOpt{"": {}, "ruler": ruler, "alertmanager": alertmanager} {
+		endpoint, err := newAPIEndpoint(opt, v, tokenSource)
+		if err != nil {
+			if component == "" {
+				component = "default"
+			}
+			return nil, fmt.Errorf("Invalid %s endpoint: %v", component, err)
+		}
+		endpoints[component] = endpoint
+	}
+
+	headers := make(map[string]string)
+	for n, v := range opt.headers {
+		headers[n] = v
+	}
+
+	client := api_client{
+		uri:            opt.uri,
+		headers:        headers,
+		debug:          opt.debug,
+		endpoints:      endpoints,
+		limiter:        newRequestLimiter(opt.max_concurrent_requests, opt.requests_per_second),
+		retries:        opt.retries,
+		retry_wait_min: opt.retry_wait_min,
+		retry_wait_max: opt.retry_wait_max,
+
+		enforced_matchers:         opt.enforced_matchers,
+		default_alert_labels:      opt.default_alert_labels,
+		default_alert_annotations: opt.default_alert_annotations,
+		alert_policy:              opt.alert_policy,
+		rule_dependencies_check:   opt.rule_dependencies_check,
+		duplicate_rules_check:     opt.duplicate_rules_check,
+		rule_groups:               &ruleGroupsCache{},
+	}
+
+	return &client, nil
+}
+
+// newAPIEndpoint builds the endpoint of a component from its settings and the
+// top-level ones. The requests get their token from the OAuth2 token source,
+// if any, unless the component sets its own token or basic auth.
+func newAPIEndpoint(opt *apiClientOpt, v *apiEndpointOpt, tokenSource *apiTokenSource) (*apiEndpoint, error) {
+	uri := v.uri
+	if uri == "" {
+		uri = opt.uri
+	}
+	uri = strings.TrimSuffix(uri, "/")
+
+	cert, key := v.cert, v.key
+	if cert == "" && key == "" {
+		cert, key = opt.cert, opt.key
+	}
+	ca := v.ca
+	if ca == "" {
+		ca = opt.ca
+	}
+	timeout := v.timeout
+	if timeout == 0 {
+		timeout = opt.timeout
+	}
+
+	// A block can enable or disable the TLS verification of the top-level
+	// setting.
+	insecure := opt.insecure
+	if v.insecure != nil {
+		insecure = *v.insecure
+	}
+
 	// Setup HTTPS client
+	tlsConfig, err := newTLSConfig(cert, key, ca, insecure)
+	if err != nil {
+		return nil, err
+	}
+
+	var tr http.RoundTripper = &http.Transport{
+		TLSClientConfig: tlsConfig,
+	}
+
+	endpoint := &apiEndpoint{
+		uri:      uri,
+		token:    opt.token,
+		username: opt.username,
+		password: opt.password,
+		headers:  make(map[string]string),
+	}
+	if v.token != "" || v.username != "" || v.password != "" {
+		endpoint.token, endpoint.username, endpoint.password = v.token, v.username, v.password
+	} else if tokenSource != nil {
+		endpoint.token, endpoint.username, endpoint.password = "", "", ""
+		tr = &apiTokenTransport{
+			source: tokenSource,
+			base:   tr,
+		}
+	}
+	endpoint.http_client = &http.Client{
+		Timeout:   time.Second * time.Duration(timeout),
+		Transport: tr,
+	}
+	for n, val := range opt.headers {
+		endpoint.headers[n] = val
+	}
+	for n, val := range v.headers {
+		endpoint.headers[n] = val
+	}
+
+	return endpoint, nil
+}
+
+// apiTokenSource fetches the access tokens with the client credentials grant
+// and caches them until they expire. Unlike the oauth2 token sources, it
+// fetches the tokens with the context of the request, so that they are
+// cancelled and time out with it.
+type apiTokenSource struct {
+	config *clientcredentials.Config
+	client *http.Client
+
+	mu    sync.Mutex
+	token *xoauth2.Token
+}
+
+// newOAuth2TokenSource returns the source of the access tokens obtained with
+// the client credentials grant, refreshed before they expire.
+func newOAuth2TokenSource(opt *apiOAuth2Opt, timeout int) (*apiTokenSource, error) {
+	tlsConfig, err := newTLSConfig(opt.cert, opt.key, opt.ca, opt.insecure)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make(url.Values)
+	for n, v := range opt.endpoint_params {
+		params.Set(n, v)
+	}
+
+	return &apiTokenSource{
+		config: &clientcredentials.Config{
+			ClientID:       opt.client_id,
+			ClientSecret:   opt.client_secret,
+			TokenURL:       opt.token_url,
+			Scopes:         opt.scopes,
+			EndpointParams: params,
+		},
+		client: &http.Client{
+			Timeout: time.Second * time.Duration(timeout),
+			Transport: &http.Transport{
+				TLSClientConfig: tlsConfig,
+			},
+		},
+	}, nil
+}
+
+// Token returns the cached token, or fetches a new one when it expires.
+func (s *apiTokenSource) Token(ctx context.Context) (*xoauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token.Valid() {
+		return s.token, nil
+	}
+	token, err := s.config.Token(context.WithValue(ctx, xoauth2.HTTPClient, s.client))
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+
+	return token, nil
+}
+
+// apiTokenTransport sets the access token of the source on the requests.
+type apiTokenTransport struct {
+	source *apiTokenSource
+	base   http.RoundTripper
+}
+
+func (t *apiTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token(req.Context())
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	// A RoundTripper must not modify the request.
+	req = req.Clone(req.Context())
+	token.SetAuthHeader(req)
+
+	return t.base.RoundTrip(req)
+}
+
+// newTLSConfig builds the TLS config of a client certificate and a CA, given
+// as PEM or as file paths.
+func newTLSConfig(cert, key, ca string, insecure bool) (*tls.Config, error) {
 	tlsConfig := &tls.Config{
-		InsecureSkipVerify: opt.insecure,
+		InsecureSkipVerify: insecure,
 	}
 
-	if opt.cert != "" && opt.key != "" {
-		var cert tls.Certificate
+	if cert != "" && key != "" {
+		var keyPair tls.Certificate
 		var err error
-		if strings.HasPrefix(opt.cert, "-----BEGIN") && strings.HasPrefix(opt.key, "-----BEGIN") {
-			cert, err = tls.X509KeyPair([]byte(opt.cert), []byte(opt.key))
+		if strings.HasPrefix(cert, "-----BEGIN") && strings.HasPrefix(key, "-----BEGIN") {
+			keyPair, err = tls.X509KeyPair([]byte(cert), []byte(key))
 		} else {
-			cert, err = tls.LoadX509KeyPair(opt.cert, opt.key)
+			keyPair, err = tls.LoadX509KeyPair(cert, key)
 		}
 		if err != nil {
 			return nil, err
 		}
-		tlsConfig.Certificates = []tls.Certificate{cert}
+		tlsConfig.Certificates = []tls.Certificate{keyPair}
 		tlsConfig.BuildNameToCertificate()
 	}
 
-	if opt.ca != "" {
+	if ca != "" {
 		var caCert []byte
 		var err error
-		if strings.HasPrefix(opt.ca, "-----BEGIN") {
-			caCert = []byte(opt.ca)
+		if strings.HasPrefix(ca, "-----BEGIN") {
+			caCert = []byte(ca)
 		} else {
-			caCert, err = ioutil.ReadFile(opt.ca)
+			caCert, err = ioutil.ReadFile(ca)
 
 			if err != nil {
 				return nil, err
@@ -100,68 +400,79 @@ func NewAPIClient(opt *apiClientOpt) (*api_client, error) {
 		tlsConfig.BuildNameToCertificate()
 	}
 
-	tr := &http.Transport{
-		TLSClientConfig: tlsConfig,
-	}
+	return tlsConfig, nil
+}
 
-	client := api_client{
-		http_client: &http.Client{
-			Timeout:   time.Second * time.Duration(opt.timeout),
-			Transport: tr,
-		},
-		uri:              opt.uri,
-		ruler_uri:        opt.ruler_uri,
-		alertmanager_uri: opt.alertmanager_uri,
-		insecure:         opt.insecure,
-		token:            opt.token,
-		username:         opt.username,
-		password:         opt.password,
-		headers:          opt.headers,
-		debug:            opt.debug,
+/* Helper function that handles sending/receiving and handling
+   of HTTP data in and out. Failed requests are retried when
+   it is safe to send them again. */
+func (client *api_client) send_request(ctx context.Context, component, method string, path, data string, headers map[string]string) (string, error) {
+	endpoint, ok := client.endpoints[component]
+	if !ok {
+		endpoint = client.endpoints[""]
 	}
+	full_uri := endpoint.uri + path
 
-	return &client, nil
-}
+	for attempt := 0; ; attempt++ {
+		if err := client.limiter.acquire(ctx); err != nil {
+			return "", err
+		}
+		resp, body, err := client.do_request(ctx, endpoint, method, full_uri, data, headers)
+		client.limiter.release()
+
+		if attempt < client.retries && ctx.Err() == nil && is_retryable_request(component, method) && is_retryable_response(resp, err) {
+			wait := client.retry_wait(attempt, resp)
+			log.Printf("api_client.go: Retrying %s %s in %s (%d/%d)\n", method, full_uri, wait, attempt+1, client.retries)
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return "", ctx.Err()
+			case <-timer.C:
+			}
+			continue
+		}
 
-/* Helper function that handles sending/receiving and handling
-   of HTTP data in and out. */
-func (client *api_client) send_request(component, method string, path, data string, headers map[string]string) (string, error) {
-	var full_uri string
-
-	if component == "ruler" && client.ruler_uri != "" {
-		full_uri = client.ruler_uri + path
-	} else if component == "alertmanager" && client.ruler_uri != "" {
-		full_uri = client.alertmanager_uri + path
-	} else {
-		full_uri = client.uri + path
+		if err != nil {
+			return "", err
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return body, newAPIError(component, method, full_uri, resp.StatusCode, body)
+		}
+
+		return body, nil
 	}
+}
 
+// Sends a request once and returns the response, whose body
+// is read and closed.
+func (client *api_client) do_request(ctx context.Context, endpoint *apiEndpoint, method, full_uri, data string, headers map[string]string) (*http.Response, string, error) {
 	var req *http.Request
 	var err error
 
 	buffer := bytes.NewBuffer([]byte(data))
 
 	if data == "" {
-		req, err = http.NewRequest(method, full_uri, nil)
+		req, err = http.NewRequestWithContext(ctx, method, full_uri, nil)
 	} else {
-		req, err = http.NewRequest(method, full_uri, buffer)
+		req, err = http.NewRequestWithContext(ctx, method, full_uri, buffer)
 	}
 
 	if err != nil {
-		log.Fatal(err)
-	}
-
-	if client.token != "" {
-		client.headers["Authorization"] = "Bearer " + client.token
+		return nil, "", fmt.Errorf("Cannot create %s request to %s: %v", method, full_uri, err)
 	}
 
 	// Set client headers from provider
-	if len(client.headers) > 0 {
-		for n, v := range client.headers {
+	if len(endpoint.headers) > 0 {
+		for n, v := range endpoint.headers {
 			req.Header.Set(n, v)
 		}
 	}
 
+	if endpoint.token != "" {
+		req.Header.Set("Authorization", "Bearer "+endpoint.token)
+	}
+
 	// Set client headers from resource
 	if len(headers) > 0 {
 		for n, v := range headers {
@@ -169,50 +480,219 @@ func (client *api_client) send_request(component, method string, path, data stri
 		}
 	}
 
-	if client.username != "" && client.password != "" {
+	if endpoint.username != "" && endpoint.password != "" {
 		/* ... and fall back to basic auth if configured */
-		req.SetBasicAuth(client.username, client.password)
+		req.SetBasicAuth(endpoint.username, endpoint.password)
 	}
 
 	if client.debug {
 
 		reqDump, err := httputil.DumpRequestOut(req, true)
 		if err != nil {
-			log.Fatal(err)
+			log.Printf("api_client.go: Cannot dump request: %s\n", err)
+		} else {
+			log.Printf("REQUEST:\n%s", string(reqDump))
 		}
-
-		log.Printf("REQUEST:\n%s", string(reqDump))
 	}
 
-	resp, err := client.http_client.Do(req)
+	resp, err := endpoint.http_client.Do(req)
 
 	if err != nil {
 		log.Printf("api_client.go: Error detected: %s\n", err)
-		return "", err
+		return nil, "", err
 	}
 
 	if client.debug {
 
 		respDump, err := httputil.DumpResponse(resp, true)
 		if err != nil {
-			log.Fatal(err)
+			log.Printf("api_client.go: Cannot dump response: %s\n", err)
+		} else {
+			log.Printf("RESPONSE:\n%s", string(respDump))
 		}
-
-		log.Printf("RESPONSE:\n%s", string(respDump))
 	}
 
 	bodyBytes, err2 := ioutil.ReadAll(resp.Body)
 	resp.Body.Close()
 
 	if err2 != nil {
-		return "", err2
+		return resp, "", err2
 	}
-	body := string(bodyBytes)
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return body, errors.New(fmt.Sprintf("Unexpected response code '%d': %s", resp.StatusCode, body))
+	return resp, string(bodyBytes), nil
+}
+
+// Only the idempotent requests are retried, and the ruler and
+// alertmanager POSTs, which overwrite whole objects.
+func is_retryable_request(component, method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return component == "ruler" || component == "alertmanager"
 	}
+	return false
+}
 
-	return body, nil
+// Network errors, rate limiting and server errors are transient.
+func is_retryable_response(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented)
+}
 
+// Returns the Retry-After delay of the response if any, or else an
+// exponential backoff with jitter between retry_wait_min and
+// retry_wait_max.
+func (client *api_client) retry_wait(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if v := resp.Header.Get("Retry-After"); v != "" {
+			if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+				return time.Duration(seconds) * time.Second
+			}
+			if date, err := http.ParseTime(v); err == nil {
+				if wait := time.Until(date); wait > 0 {
+					return wait
+				}
+				return 0
+			}
+		}
+	}
+
+	// Compare before shifting, so that large waits or attempts cannot
+	// overflow.
+	wait := client.retry_wait_max
+	if attempt < 63 && client.retry_wait_min <= client.retry_wait_max>>uint(attempt) {
+		wait = client.retry_wait_min << uint(attempt)
+	}
+	if wait <= 0 {
+		return 0
+	}
+
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// requestLimiter bounds the requests in flight and spaces the requests
+// to stay under a rate.
+type requestLimiter struct {
+	// slots holds a value per request in flight, nil when unlimited
+	slots chan struct{}
+	// interval between the requests, 0 when unlimited
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func newRequestLimiter(concurrency int, rate float64) *requestLimiter {
+	l := &requestLimiter{}
+	if concurrency > 0 {
+		l.slots = make(chan struct{}, concurrency)
+	}
+	if rate > 0 {
+		l.interval = time.Duration(float64(time.Second) / rate)
+	}
+	return l
+}
+
+// acquire waits for a free slot and for the turn of the request, or until
+// the context is done.
+func (l *requestLimiter) acquire(ctx context.Context) error {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if l.interval == 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	at := l.next
+	if now := time.Now(); at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	if wait := time.Until(at); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			l.release()
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// release frees the slot of a request once it is done.
+func (l *requestLimiter) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// apiError is the error of a request answered with a non-2xx status code.
+type apiError struct {
+	StatusCode int
+	Component  string
+	Method     string
+	URL        string
+	// Message is the error parsed from the response body
+	Message string
+	Body    string
+}
+
+func newAPIError(component, method, url string, statusCode int, body string) *apiError {
+	if component == "" {
+		component = "mimir"
+	}
+	return &apiError{
+		StatusCode: statusCode,
+		Component:  component,
+		Method:     method,
+		URL:        url,
+		Message:    parseAPIErrorBody(body),
+		Body:       body,
+	}
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("Unexpected response code '%d' from %s %s %s: %s", e.StatusCode, e.Component, e.Method, e.URL, e.Message)
+}
+
+// Hint returns what to check to fix the error, based on its status code.
+func (e *apiError) Hint() string {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return "Check the credentials and the org_id of the provider."
+	case e.StatusCode == http.StatusNotFound:
+		return fmt.Sprintf("Check the %s URL of the provider and that the %s is enabled.", e.Component, e.Component)
+	case e.StatusCode == http.StatusTooManyRequests:
+		return "The tenant is rate limited, increase the retries of the provider or the limits of the tenant."
+	case e.StatusCode >= 500:
+		return fmt.Sprintf("The %s failed to handle the request, check its logs.", e.Component)
+	case e.StatusCode >= 400:
+		return fmt.Sprintf("The %s rejected the request, check the configuration.", e.Component)
+	}
+	return ""
+}
+
+// parseAPIErrorBody returns the error of a response body, either the error
+// field of a Prometheus API response or the trimmed text.
+func parseAPIErrorBody(body string) string {
+	var data struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(body), &data); err == nil && data.Error != "" {
+		return data.Error
+	}
+	return strings.TrimSpace(body)
 }
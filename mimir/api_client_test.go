@@ -3,13 +3,18 @@ package mimir
 // Largely copied from https://github.com/Mastercard/terraform-provider-restapi/blob/master/restapi/api_client_test.go
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
 
 var api_client_server *http.Server
+var api_client_server_hits = make(map[string]int)
+var api_client_server_in_flight, api_client_server_max_in_flight int32
 
 func TestAPIClient(t *testing.T) {
 	debug := false
@@ -35,6 +40,9 @@ func TestAPIClient(t *testing.T) {
 		headers:          make(map[string]string, 0),
 		timeout:          2,
 		debug:            debug,
+		retries:          2,
+		retry_wait_min:   10 * time.Millisecond,
+		retry_wait_max:   50 * time.Millisecond,
 	}
 	client, _ := NewAPIClient(opt)
 
@@ -45,7 +53,7 @@ func TestAPIClient(t *testing.T) {
 		log.Printf("api_client_test.go: Testing standard OK request\n")
 	}
 	var headers map[string]string
-	res, err = client.send_request("", "GET", "/ok", "", headers)
+	res, err = client.send_request(context.Background(), "", "GET", "/ok", "", headers)
 	if err != nil {
 		t.Fatalf("client_test.go: %s", err)
 	}
@@ -56,7 +64,7 @@ func TestAPIClient(t *testing.T) {
 	if debug {
 		log.Printf("api_client_test.go: Testing redirect request\n")
 	}
-	res, err = client.send_request("", "GET", "/redirect", "", headers)
+	res, err = client.send_request(context.Background(), "", "GET", "/redirect", "", headers)
 	if err != nil {
 		t.Fatalf("client_test.go: %s", err)
 	}
@@ -64,11 +72,218 @@ func TestAPIClient(t *testing.T) {
 		t.Fatalf("client_test.go: Got back '%s' but expected 'It works!'\n", res)
 	}
 
+	/* Verify transient failures are retried */
+	if debug {
+		log.Printf("api_client_test.go: Testing retries of failed requests\n")
+	}
+	res, err = client.send_request(context.Background(), "", "GET", "/flaky", "", headers)
+	if err != nil {
+		t.Fatalf("client_test.go: %s", err)
+	}
+	if res != "It works!" {
+		t.Fatalf("client_test.go: Got back '%s' but expected 'It works!'\n", res)
+	}
+
+	_, err = client.send_request(context.Background(), "ruler", "POST", "/unavailable", "", headers)
+	if err == nil {
+		t.Fatalf("client_test.go: Request to unavailable server did not fail")
+	}
+	if api_client_server_hits["/unavailable"] != 3 {
+		t.Fatalf("client_test.go: Got %d attempts but expected 3\n", api_client_server_hits["/unavailable"])
+	}
+
+	/* POSTs outside the ruler and the alertmanager are not idempotent */
+	api_client_server_hits["/unavailable"] = 0
+	_, err = client.send_request(context.Background(), "", "POST", "/unavailable", "", headers)
+	if err == nil {
+		t.Fatalf("client_test.go: Request to unavailable server did not fail")
+	}
+	if api_client_server_hits["/unavailable"] != 1 {
+		t.Fatalf("client_test.go: Got %d attempts but expected 1\n", api_client_server_hits["/unavailable"])
+	}
+
+	/* Verify errors carry the response */
+	_, err = client.send_request(context.Background(), "ruler", "GET", "/missing", "", headers)
+	if !isNotFoundError(err) {
+		t.Fatalf("client_test.go: Got '%v' but expected a not found error\n", err)
+	}
+	_, err = client.send_request(context.Background(), "ruler", "POST", "/invalid", "", headers)
+	apiErr, ok := err.(*apiError)
+	if !ok {
+		t.Fatalf("client_test.go: Got '%v' but expected an API error\n", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Message != "invalid rule group" {
+		t.Fatalf("client_test.go: Got %d '%s' but expected 400 'invalid rule group'\n", apiErr.StatusCode, apiErr.Message)
+	}
+
+	/* Cancelled requests are neither sent nor retried */
+	api_client_server_hits["/unavailable"] = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.send_request(ctx, "", "GET", "/unavailable", "", headers)
+	if err == nil {
+		t.Fatalf("client_test.go: Cancelled request did not fail")
+	}
+	if api_client_server_hits["/unavailable"] != 0 {
+		t.Fatalf("client_test.go: Got %d attempts but expected 0\n", api_client_server_hits["/unavailable"])
+	}
+
+	/* Verify each component reaches its own endpoint */
+	if debug {
+		log.Printf("api_client_test.go: Testing component endpoints\n")
+	}
+	componentClient, err := NewAPIClient(&apiClientOpt{
+		uri:              "http://127.0.0.1:1",
+		alertmanager_uri: "http://127.0.0.1:8082/",
+		token:            "token",
+		headers:          make(map[string]string, 0),
+		timeout:          2,
+		debug:            debug,
+		ruler: &apiEndpointOpt{
+			uri:   "http://127.0.0.1:8082",
+			token: "ruler-token",
+		},
+	})
+	if err != nil {
+		t.Fatalf("client_test.go: %s", err)
+	}
+	res, err = componentClient.send_request(context.Background(), "alertmanager", "GET", "/authorization", "", headers)
+	if err != nil {
+		t.Fatalf("client_test.go: %s", err)
+	}
+	if res != "Bearer token" {
+		t.Fatalf("client_test.go: Got back '%s' but expected 'Bearer token'\n", res)
+	}
+	res, err = componentClient.send_request(context.Background(), "ruler", "GET", "/authorization", "", headers)
+	if err != nil {
+		t.Fatalf("client_test.go: %s", err)
+	}
+	if res != "Bearer ruler-token" {
+		t.Fatalf("client_test.go: Got back '%s' but expected 'Bearer ruler-token'\n", res)
+	}
+
+	/* Verify the OAuth2 access tokens are fetched once and sent */
+	if debug {
+		log.Printf("api_client_test.go: Testing OAuth2 authentication\n")
+	}
+	oauth2Client, err := NewAPIClient(&apiClientOpt{
+		uri:     "http://127.0.0.1:8082/",
+		headers: make(map[string]string, 0),
+		token:   "token",
+		timeout: 2,
+		debug:   debug,
+		oauth2: &apiOAuth2Opt{
+			token_url:       "http://127.0.0.1:8082/token",
+			client_id:       "mimir",
+			client_secret:   "secret",
+			scopes:          []string{"rules"},
+			endpoint_params: map[string]string{"audience": "mimir"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("client_test.go: %s", err)
+	}
+	for i := 0; i < 2; i++ {
+		res, err = oauth2Client.send_request(context.Background(), "ruler", "GET", "/authorization", "", headers)
+		if err != nil {
+			t.Fatalf("client_test.go: %s", err)
+		}
+		if res != "Bearer access-token" {
+			t.Fatalf("client_test.go: Got back '%s' but expected 'Bearer access-token'\n", res)
+		}
+	}
+	if api_client_server_hits["/token"] != 1 {
+		t.Fatalf("client_test.go: Got %d token requests but expected 1\n", api_client_server_hits["/token"])
+	}
+
+	/* Verify the token requests are cancelled with the request */
+	slowTokenClient, err := NewAPIClient(&apiClientOpt{
+		uri:     "http://127.0.0.1:8082/",
+		headers: make(map[string]string, 0),
+		timeout: 2,
+		debug:   debug,
+		oauth2: &apiOAuth2Opt{
+			token_url: "http://127.0.0.1:8082/slow",
+			client_id: "mimir",
+		},
+	})
+	if err != nil {
+		t.Fatalf("client_test.go: %s", err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	start := time.Now()
+	_, err = slowTokenClient.send_request(ctx, "ruler", "GET", "/authorization", "", headers)
+	cancel()
+	if err == nil {
+		t.Fatalf("client_test.go: Token request did not fail with the request context\n")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("client_test.go: Token request took %s but expected to be cancelled after 100ms\n", elapsed)
+	}
+
+	/* Verify the limits of the concurrent requests */
+	if debug {
+		log.Printf("api_client_test.go: Testing request limits\n")
+	}
+	limitedClient, _ := NewAPIClient(&apiClientOpt{
+		uri:                     "http://127.0.0.1:8082/",
+		headers:                 map[string]string{"X-Scope-OrgID": "test"},
+		token:                   "token",
+		timeout:                 2,
+		debug:                   debug,
+		max_concurrent_requests: 2,
+		requests_per_second:     50,
+	})
+	start = time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := limitedClient.send_request(context.Background(), "ruler", "GET", "/concurrent", "", headers); err != nil {
+				t.Errorf("client_test.go: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if max := atomic.LoadInt32(&api_client_server_max_in_flight); max > 2 {
+		t.Fatalf("client_test.go: Got %d requests in flight but expected at most 2\n", max)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Fatalf("client_test.go: 6 requests took %s but expected at least 100ms at 50 requests per second\n", elapsed)
+	}
+
+	/* Verify the rule groups are read once by the checks */
+	if debug {
+		log.Printf("api_client_test.go: Testing rule groups cache\n")
+	}
+	for i := 0; i < 2; i++ {
+		groups, err := ruleGroupsWith(context.Background(), client, "ns", "new", []rulesFileRule{{Record: "job:up:sum", Expr: "sum by (job) (up)"}})
+		if err != nil {
+			t.Fatalf("client_test.go: %s", err)
+		}
+		if len(groups["ns"]) != 2 || groups["ns"][1].Name != "new" {
+			t.Fatalf("client_test.go: Got rule groups %v but expected the new group to be added\n", groups)
+		}
+	}
+	if hits := api_client_server_hits["/config/v1/rules"]; hits != 1 {
+		t.Fatalf("client_test.go: Rule groups were read %d times but expected once\n", hits)
+	}
+	client.rule_groups.set("ns", "name: new\nrules:\n  - record: job:up:sum\n    expr: sum by (job) (up)\n")
+	client.rule_groups.delete("ns", "existing")
+	groups, err := client.rule_groups.get(context.Background(), client)
+	if err != nil {
+		t.Fatalf("client_test.go: %s", err)
+	}
+	if len(groups["ns"]) != 1 || groups["ns"][0].Name != "new" {
+		t.Fatalf("client_test.go: Got rule groups %v but expected only the new group\n", groups)
+	}
+
 	/* Verify timeout works */
 	if debug {
 		log.Printf("api_client_test.go: Testing timeout aborts requests\n")
 	}
-	_, err = client.send_request("", "GET", "/slow", "", headers)
+	_, err = client.send_request(context.Background(), "", "GET", "/slow", "", headers)
 	if err == nil {
 		t.Fatalf("client_test.go: Timeout did not trigger on slow request")
 	}
@@ -87,6 +302,52 @@ func setup_api_client_server() {
 		time.Sleep(9999 * time.Second)
 		w.Write([]byte("This will never return!!!!!"))
 	})
+	serverMux.HandleFunc("/flaky", func(w http.ResponseWriter, r *http.Request) {
+		api_client_server_hits["/flaky"]++
+		if api_client_server_hits["/flaky"] == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("It works!"))
+	})
+	serverMux.HandleFunc("/unavailable", func(w http.ResponseWriter, r *http.Request) {
+		api_client_server_hits["/unavailable"]++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	serverMux.HandleFunc("/invalid", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"invalid rule group"}`))
+	})
+	serverMux.HandleFunc("/authorization", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("Authorization")))
+	})
+	serverMux.HandleFunc("/concurrent", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&api_client_server_in_flight, 1)
+		defer atomic.AddInt32(&api_client_server_in_flight, -1)
+		for {
+			max := atomic.LoadInt32(&api_client_server_max_in_flight)
+			if n <= max || atomic.CompareAndSwapInt32(&api_client_server_max_in_flight, max, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte("It works!"))
+	})
+	serverMux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		api_client_server_hits["/token"]++
+		clientID, clientSecret, _ := r.BasicAuth()
+		if clientID != "mimir" || clientSecret != "secret" || r.FormValue("scope") != "rules" || r.FormValue("audience") != "mimir" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"access-token","token_type":"Bearer","expires_in":3600}`))
+	})
+	serverMux.HandleFunc("/config/v1/rules", func(w http.ResponseWriter, r *http.Request) {
+		api_client_server_hits["/config/v1/rules"]++
+		w.Write([]byte("ns:\n  - name: existing\n    rules:\n      - record: instance:up:sum\n        expr: sum by (instance) (up)\n"))
+	})
 	serverMux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/ok", http.StatusPermanentRedirect)
 	})
@@ -103,3 +364,57 @@ func setup_api_client_server() {
 func shutdown_api_client_server() {
 	api_client_server.Close()
 }
+
+func TestAPIClientRetryWait(t *testing.T) {
+	tests := []struct {
+		name    string
+		min     time.Duration
+		max     time.Duration
+		attempt int
+		want    time.Duration
+	}{
+		{name: "first attempt", min: time.Second, max: 30 * time.Second, attempt: 0, want: time.Second},
+		{name: "doubled", min: time.Second, max: 30 * time.Second, attempt: 3, want: 8 * time.Second},
+		{name: "capped", min: time.Second, max: 30 * time.Second, attempt: 5, want: 30 * time.Second},
+		{name: "large wait", min: 30 * time.Second, max: time.Hour, attempt: 31, want: time.Hour},
+		{name: "large attempt", min: time.Second, max: 30 * time.Second, attempt: 100, want: 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &api_client{retry_wait_min: tt.min, retry_wait_max: tt.max}
+			got := client.retry_wait(tt.attempt, nil)
+			if got < tt.want/2 || got > tt.want {
+				t.Fatalf("Got a wait of %s but expected between %s and %s", got, tt.want/2, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewAPIEndpointInsecure(t *testing.T) {
+	enabled, disabled := true, false
+	tests := []struct {
+		name     string
+		top      bool
+		block    *bool
+		expected bool
+	}{
+		{name: "top-level", top: true, expected: true},
+		{name: "block enables", block: &enabled, expected: true},
+		{name: "block disables", top: true, block: &disabled, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := &apiClientOpt{uri: "https://127.0.0.1", insecure: tt.top, timeout: 2}
+			endpoint, err := newAPIEndpoint(opt, &apiEndpointOpt{insecure: tt.block}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := endpoint.http_client.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify
+			if got != tt.expected {
+				t.Fatalf("Got InsecureSkipVerify %v but expected %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -1,34 +1,35 @@
 package mimir
 
 import (
+	"context"
 	"fmt"
-	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"gopkg.in/yaml.v3"
 )
 
 func dataSourcemimirAlertmanagerConfig() *schema.Resource {
 	return &schema.Resource{
-		Read:   dataSourcemimirAlertmanagerConfigRead,
-		Schema: dataSourceMimirAlertmanagerConfigSchemaV1(),
+		ReadContext: dataSourcemimirAlertmanagerConfigRead,
+		Schema:      dataSourceMimirAlertmanagerConfigSchemaV1(),
 	}
 }
 
-func dataSourcemimirAlertmanagerConfigRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourcemimirAlertmanagerConfigRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*api_client)
 	name := d.Get("name").(string)
 	path := "/api/v1/alerts"
-	resp, err := client.send_request("alertmanager", "GET", path, "", make(map[string]string))
+	resp, err := client.send_request(ctx, "alertmanager", "GET", path, "", make(map[string]string))
 	baseMsg := "Cannot read alertmanager config"
 	fullurl := fmt.Sprintf("%s%s", client.uri, path)
 	err = handleHTTPError(err, resp, fullurl, baseMsg)
 	if err != nil {
-		if strings.Contains(err.Error(), "response code '404'") {
+		if isNotFoundError(err) {
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromErr(err)
 	}
 
 	d.SetId(client.headers["X-Scope-OrgID"])
@@ -48,9 +49,21 @@ func dataSourcemimirAlertmanagerConfigRead(d *schema.ResourceData, meta interfac
 	if alertmanagerConf.Global != nil {
 		d.Set("global", flattenGlobalConfig(alertmanagerConf.Global))
 	}
-	d.Set("time_interval", flattenMuteTimeIntervalConfig(alertmanagerConf.MuteTimeIntervals))
+	timeIntervalsKey, err := alertmanagerTimeIntervalsKey(&alertmanagerConf)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if timeIntervalsKey == "" {
+		timeIntervalsKey = "mute_time_intervals"
+	}
+	d.Set("time_interval", flattenMuteTimeIntervalConfig(append(alertmanagerConf.MuteTimeIntervals, alertmanagerConf.TimeIntervals...)))
+	d.Set("time_intervals_key", timeIntervalsKey)
 	d.Set("inhibit_rule", flattenInhibitRuleConfig(alertmanagerConf.InhibitRules))
-	d.Set("receiver", flattenReceiverConfig(alertmanagerConf.Receivers))
+	receivers, err := flattenReceiverConfig(alertmanagerConf.Receivers)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("receiver", receivers)
 	d.Set("route", flattenRouteConfig(alertmanagerConf.Route))
 	d.Set("templates", alertmanagerConf.Templates)
 	d.Set("templates_files", alertmanagerUserConf.TemplateFiles)
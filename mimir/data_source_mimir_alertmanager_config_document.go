@@ -0,0 +1,31 @@
+package mimir
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v3"
+)
+
+func dataSourcemimirAlertmanagerConfigDocument() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcemimirAlertmanagerConfigDocumentRead,
+		Schema:      dataSourceMimirAlertmanagerConfigDocumentSchemaV1(),
+	}
+}
+
+func dataSourcemimirAlertmanagerConfigDocumentRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	alertmanagerUserConf := expandAlertmanagerUserConfig(d)
+
+	content, err := yaml.Marshal(&alertmanagerUserConf)
+	if err != nil {
+		return diag.Errorf("Cannot marshal alertmanager config: %v", err)
+	}
+
+	d.SetId(hashString(string(content)))
+	d.Set("alertmanager_config", alertmanagerUserConf.AlertmanagerConfig)
+	d.Set("content", string(content))
+
+	return diag.Diagnostics{}
+}
@@ -0,0 +1,63 @@
+package mimir
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceAlertmanagerConfigDocument_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAlertmanagerConfigDocument_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mimir_alertmanager_config_document.mytenant", "alertmanager_config", testAccDataSourceAlertmanagerConfigDocument_expected),
+					resource.TestMatchResourceAttr("data.mimir_alertmanager_config_document.mytenant", "content", regexp.MustCompile(`(?m)^template_files:\n    default.tmpl: '\{\{ define "subject" \}\}alert\{\{ end \}\}'\n`)),
+					resource.TestMatchResourceAttr("data.mimir_alertmanager_config_document.mytenant", "content", regexp.MustCompile(`(?m)^alertmanager_config: \|\n    route:\n`)),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceAlertmanagerConfigDocument_basic = `
+	data "mimir_alertmanager_config_document" "mytenant" {
+		route {
+			group_by = ["..."]
+			group_wait = "30s"
+			group_interval = "5m"
+			repeat_interval = "1h"
+			receiver = "pagerduty"
+		}
+		receiver {
+			name = "pagerduty"
+			pagerduty_configs {
+				routing_key = "secret"
+			}
+		}
+		templates = ["default.tmpl"]
+		templates_files = {
+			"default.tmpl" = "{{ define \"subject\" }}alert{{ end }}"
+		}
+	}
+`
+
+const testAccDataSourceAlertmanagerConfigDocument_expected = `route:
+    receiver: pagerduty
+    group_by:
+        - '...'
+    group_wait: 30s
+    group_interval: 5m
+    repeat_interval: 1h
+receivers:
+    - name: pagerduty
+      pagerduty_configs:
+        - send_resolved: true
+          routing_key: secret
+templates:
+    - default.tmpl
+`
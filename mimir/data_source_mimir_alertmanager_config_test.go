@@ -36,3 +36,53 @@ var testAccDataSourceAlertmanagerConfig_basic = fmt.Sprintf(`
 		name = "${mimir_alertmanager_config.mytenant.id}"
 	}
 `, testAccResourceAlertmanagerConfig_basic)
+
+func TestAccDataSourceAlertmanagerConfig_msteams(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAlertmanagerConfig_msteams,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mimir_alertmanager_config.mytenant", "receiver.0.name", "msteams"),
+					resource.TestCheckResourceAttr("data.mimir_alertmanager_config.mytenant", "receiver.0.msteams_configs.0.webhook_url", "https://example.webhook.office.com/webhookb2/secret"),
+					resource.TestCheckResourceAttr("data.mimir_alertmanager_config.mytenant", "receiver.0.msteams_configs.0.title", "{{ .CommonLabels.alertname }}"),
+				),
+			},
+		},
+	})
+}
+
+var testAccDataSourceAlertmanagerConfig_msteams = fmt.Sprintf(`
+	%s
+
+	data "mimir_alertmanager_config" "mytenant" {
+		name = "${mimir_alertmanager_config.mytenant.id}"
+	}
+`, testAccResourceAlertmanagerConfig_MSTeamsReceiver)
+
+func TestAccDataSourceAlertmanagerConfig_jira(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAlertmanagerConfig_jira,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mimir_alertmanager_config.mytenant", "receiver.0.name", "jira"),
+					resource.TestCheckResourceAttr("data.mimir_alertmanager_config.mytenant", "receiver.0.jira_configs.0.project", "OPS"),
+					resource.TestCheckResourceAttr("data.mimir_alertmanager_config.mytenant", "receiver.0.jira_configs.0.fields.customfield_10001", `{"value":"production"}`),
+				),
+			},
+		},
+	})
+}
+
+var testAccDataSourceAlertmanagerConfig_jira = fmt.Sprintf(`
+	%s
+
+	data "mimir_alertmanager_config" "mytenant" {
+		name = "${mimir_alertmanager_config.mytenant.id}"
+	}
+`, testAccResourceAlertmanagerConfig_JiraReceiver)
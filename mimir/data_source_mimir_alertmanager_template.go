@@ -0,0 +1,309 @@
+package mimir
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+)
+
+func dataSourcemimirAlertmanagerTemplate() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcemimirAlertmanagerTemplateRead,
+
+		Schema: map[string]*schema.Schema{
+			"templates_files": {
+				Type:        schema.TypeMap,
+				Required:    true,
+				Description: "A map of key values string, where the key is the template name and the value the content of the template.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"template": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Name of a template defined in `templates_files` to render.",
+				AtLeastOneOf: []string{"template", "fields"},
+			},
+			"fields": {
+				Type:         schema.TypeMap,
+				Optional:     true,
+				Description:  "A map of template strings to render, such as the `title` and `text` fields of a receiver.",
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				AtLeastOneOf: []string{"template", "fields"},
+			},
+			"data": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "The sample alert group passed to the templates.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"receiver": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Name of the receiver the notification is sent to.",
+						},
+						"group_labels": {
+							Type:         schema.TypeMap,
+							Optional:     true,
+							Description:  "Labels the alert group is grouped by.",
+							Elem:         &schema.Schema{Type: schema.TypeString},
+							ValidateFunc: validateLabels,
+						},
+						"external_url": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The external URL of the alertmanager.",
+						},
+						"alert": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "A list of alerts in the group.",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"status": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										Default:      string(model.AlertFiring),
+										Description:  "The status of the alert, either `firing` or `resolved`.",
+										ValidateFunc: validation.StringInSlice([]string{string(model.AlertFiring), string(model.AlertResolved)}, false),
+									},
+									"labels": {
+										Type:         schema.TypeMap,
+										Optional:     true,
+										Description:  "Labels of the alert.",
+										Elem:         &schema.Schema{Type: schema.TypeString},
+										ValidateFunc: validateLabels,
+									},
+									"annotations": {
+										Type:         schema.TypeMap,
+										Optional:     true,
+										Description:  "Annotations of the alert.",
+										Elem:         &schema.Schema{Type: schema.TypeString},
+										ValidateFunc: validateAnnotations,
+									},
+									"starts_at": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										Description:  "RFC3339 time the alert started firing. Defaults to the current time.",
+										ValidateFunc: validation.IsRFC3339Time,
+									},
+									"ends_at": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										Description:  "RFC3339 time the alert was resolved. Only used for resolved alerts.",
+										ValidateFunc: validation.IsRFC3339Time,
+									},
+									"generator_url": {
+										Type:        schema.TypeString,
+										Optional:    true,
+										Description: "Backlink to the sender of the alert.",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"rendered": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The output of the rendered `template`.",
+			},
+			"rendered_fields": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The output of each rendered entry of `fields`.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		}, /* End schema */
+	}
+}
+
+func dataSourcemimirAlertmanagerTemplateRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	templatesFiles := expandStringMap(d.Get("templates_files").(map[string]interface{}))
+
+	tmpl, err := loadAlertmanagerTemplates(templatesFiles)
+	if err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Cannot parse alertmanager templates",
+			Detail:        err.Error(),
+			AttributePath: cty.GetAttrPath("templates_files"),
+		}}
+	}
+
+	data, err := expandTemplateData(tmpl, d.Get("data").([]interface{}))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var diags diag.Diagnostics
+
+	rendered := ""
+	if name := d.Get("template").(string); name != "" {
+		rendered, err = tmpl.ExecuteTextString(fmt.Sprintf("{{ template %q . }}", name), data)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("Cannot render template %q", name),
+				Detail:        err.Error(),
+				AttributePath: cty.GetAttrPath("template"),
+			})
+		}
+	}
+
+	fields := expandStringMap(d.Get("fields").(map[string]interface{}))
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	renderedFields := make(map[string]string, len(fields))
+	for _, k := range keys {
+		out, err := tmpl.ExecuteTextString(fields[k], data)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("Cannot render field %q", k),
+				Detail:        err.Error(),
+				AttributePath: cty.GetAttrPath("fields").IndexString(k),
+			})
+			continue
+		}
+		renderedFields[k] = out
+	}
+
+	if diags.HasError() {
+		return diags
+	}
+
+	d.SetId(hashString(fmt.Sprintf("%v%v%v", templatesFiles, d.Get("template"), fields)))
+	d.Set("rendered", rendered)
+	d.Set("rendered_fields", renderedFields)
+
+	return diags
+}
+
+// loadAlertmanagerTemplates parses the templates the same way alertmanager
+// does, by writing each of them to a file named after its key so that parse
+// errors point at the offending template.
+func loadAlertmanagerTemplates(templatesFiles map[string]string) (*template.Template, error) {
+	dir, err := ioutil.TempDir("", "mimir-templates")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	var paths []string
+	names := make(map[string]string)
+	for name, content := range templatesFiles {
+		// The alertmanager stores the template files by base name.
+		base := filepath.Base(name)
+		if other, ok := names[base]; ok {
+			if other > name {
+				other, name = name, other
+			}
+			return nil, fmt.Errorf("Template files %q and %q have the same file name %q", other, name, base)
+		}
+		names[base] = name
+
+		path := filepath.Join(dir, base)
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return template.FromGlobs(paths...)
+}
+
+func expandTemplateData(tmpl *template.Template, v []interface{}) (*template.Data, error) {
+	var (
+		receiver    string
+		groupLabels = model.LabelSet{}
+		alerts      []*types.Alert
+	)
+	tmpl.ExternalURL = &url.URL{}
+
+	if len(v) != 0 && v[0] != nil {
+		cfg := v[0].(map[string]interface{})
+		receiver = cfg["receiver"].(string)
+
+		for k, v := range expandStringMap(cfg["group_labels"].(map[string]interface{})) {
+			groupLabels[model.LabelName(k)] = model.LabelValue(v)
+		}
+
+		if raw := cfg["external_url"].(string); raw != "" {
+			externalURL, err := url.Parse(raw)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid external_url %q: %v", raw, err)
+			}
+			tmpl.ExternalURL = externalURL
+		}
+
+		for _, raw := range cfg["alert"].([]interface{}) {
+			alert, err := expandTemplateAlert(raw.(map[string]interface{}))
+			if err != nil {
+				return nil, err
+			}
+			alerts = append(alerts, alert)
+		}
+	}
+
+	return tmpl.Data(receiver, groupLabels, alerts...), nil
+}
+
+func expandTemplateAlert(data map[string]interface{}) (*types.Alert, error) {
+	now := time.Now()
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels:       model.LabelSet{},
+			Annotations:  model.LabelSet{},
+			StartsAt:     now,
+			GeneratorURL: data["generator_url"].(string),
+		},
+	}
+
+	for k, v := range expandStringMap(data["labels"].(map[string]interface{})) {
+		alert.Labels[model.LabelName(k)] = model.LabelValue(v)
+	}
+	for k, v := range expandStringMap(data["annotations"].(map[string]interface{})) {
+		alert.Annotations[model.LabelName(k)] = model.LabelValue(v)
+	}
+
+	if raw := data["starts_at"].(string); raw != "" {
+		startsAt, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, err
+		}
+		alert.StartsAt = startsAt
+	}
+
+	if data["status"].(string) == string(model.AlertResolved) {
+		alert.EndsAt = now
+		if raw := data["ends_at"].(string); raw != "" {
+			endsAt, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return nil, err
+			}
+			alert.EndsAt = endsAt
+		}
+	}
+
+	return alert, nil
+}
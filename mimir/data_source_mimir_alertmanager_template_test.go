@@ -0,0 +1,152 @@
+package mimir
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceAlertmanagerTemplateRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      map[string]interface{}
+		rendered string
+		err      string
+	}{
+		{
+			name: "render",
+			raw: map[string]interface{}{
+				"templates_files": map[string]interface{}{
+					"templates/slack.tmpl": `{{ define "slack.title" }}{{ .Receiver }} {{ .CommonLabels.alertname }}{{ end }}`,
+				},
+				"template": "slack.title",
+				"data": []interface{}{
+					map[string]interface{}{
+						"receiver": "slack",
+						"alert": []interface{}{
+							map[string]interface{}{
+								"labels": map[string]interface{}{"alertname": "HighLatency"},
+							},
+						},
+					},
+				},
+			},
+			rendered: "slack HighLatency",
+		},
+		{
+			name: "duplicate file names",
+			raw: map[string]interface{}{
+				"templates_files": map[string]interface{}{
+					"team-a/slack.tmpl": `{{ define "slack.title" }}a{{ end }}`,
+					"team-b/slack.tmpl": `{{ define "slack.title" }}b{{ end }}`,
+				},
+				"template": "slack.title",
+			},
+			err: `Template files "team-a/slack.tmpl" and "team-b/slack.tmpl" have the same file name "slack.tmpl"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, dataSourcemimirAlertmanagerTemplate().Schema, tt.raw)
+			diags := dataSourcemimirAlertmanagerTemplateRead(context.Background(), d, nil)
+
+			if tt.err != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Detail, tt.err) {
+					t.Fatalf("Got %v but expected the error %q", diags, tt.err)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("Unexpected error: %v", diags)
+			}
+			if got := d.Get("rendered").(string); got != tt.rendered {
+				t.Fatalf("Got %q but expected %q", got, tt.rendered)
+			}
+		})
+	}
+}
+
+func TestAccDataSourceAlertmanagerTemplate_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAlertmanagerTemplate_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mimir_alertmanager_template.slack", "rendered", "[FIRING:2] HighLatency (api)"),
+					resource.TestCheckResourceAttr("data.mimir_alertmanager_template.slack", "rendered_fields.title", "HighLatency is firing"),
+					resource.TestCheckResourceAttr("data.mimir_alertmanager_template.slack", "rendered_fields.text", "Latency is high on api-1. Latency is high on api-2. "),
+				),
+			},
+			{
+				Config:      testAccDataSourceAlertmanagerTemplate_parseError,
+				ExpectError: regexp.MustCompile("broken.tmpl:1"),
+			},
+			{
+				Config:      testAccDataSourceAlertmanagerTemplate_executionError,
+				ExpectError: regexp.MustCompile(`template "undefined" not defined`),
+			},
+		},
+	})
+}
+
+const testAccDataSourceAlertmanagerTemplate_basic = `
+	data "mimir_alertmanager_template" "slack" {
+		templates_files = {
+			"slack.tmpl" = "{{ define \"slack.title\" }}[{{ .Status | toUpper }}:{{ .Alerts.Firing | len }}] {{ .CommonLabels.alertname }} ({{ .GroupLabels.service }}){{ end }}"
+		}
+		template = "slack.title"
+		fields = {
+			title = "{{ .CommonLabels.alertname }} is {{ .Status }}"
+			text  = "{{ range .Alerts }}{{ .Annotations.summary }} {{ end }}"
+		}
+		data {
+			receiver = "slack"
+			group_labels = {
+				service = "api"
+			}
+			alert {
+				labels = {
+					alertname = "HighLatency"
+					instance  = "api-1"
+				}
+				annotations = {
+					summary = "Latency is high on api-1."
+				}
+			}
+			alert {
+				labels = {
+					alertname = "HighLatency"
+					instance  = "api-2"
+				}
+				annotations = {
+					summary = "Latency is high on api-2."
+				}
+			}
+		}
+	}
+`
+
+const testAccDataSourceAlertmanagerTemplate_parseError = `
+	data "mimir_alertmanager_template" "broken" {
+		templates_files = {
+			"broken.tmpl" = "{{ define \"broken\" }}{{ .Status }"
+		}
+		template = "broken"
+	}
+`
+
+const testAccDataSourceAlertmanagerTemplate_executionError = `
+	data "mimir_alertmanager_template" "undefined" {
+		templates_files = {
+			"default.tmpl" = "{{ define \"defined\" }}ok{{ end }}"
+		}
+		template = "undefined"
+	}
+`
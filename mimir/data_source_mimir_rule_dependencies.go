@@ -0,0 +1,145 @@
+package mimir
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcemimirRuleDependencies() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcemimirRuleDependenciesRead,
+
+		Schema: map[string]*schema.Schema{
+			"rule": {
+				Type:        schema.TypeList,
+				Description: "Rules of the tenant and the recorded metrics they use.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"namespace": {
+							Type:        schema.TypeString,
+							Description: "Namespace of the rule group.",
+							Computed:    true,
+						},
+						"group": {
+							Type:        schema.TypeString,
+							Description: "Name of the rule group.",
+							Computed:    true,
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Description: "Name of the recorded metric or of the alert.",
+							Computed:    true,
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Description: "Type of the rule, `recording` or `alerting`.",
+							Computed:    true,
+						},
+						"depends_on": {
+							Type:        schema.TypeList,
+							Description: "Recorded metrics used by the expression: the metrics recorded by a rule of the tenant or named like recorded metrics (`level:metric:operations`).",
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"problem": {
+				Type:        schema.TypeList,
+				Description: "Dependency problems of the rules.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:        schema.TypeString,
+							Description: "Type of the problem: `undefined` for a recorded metric recorded by no rule, `order` for a metric recorded later in the same group and `cycle` for a dependency cycle between recorded metrics.",
+							Computed:    true,
+						},
+						"namespace": {
+							Type:        schema.TypeString,
+							Description: "Namespace of the rule group.",
+							Computed:    true,
+						},
+						"group": {
+							Type:        schema.TypeString,
+							Description: "Name of the rule group.",
+							Computed:    true,
+						},
+						"rule": {
+							Type:        schema.TypeString,
+							Description: "Name of the recorded metric or of the alert.",
+							Computed:    true,
+						},
+						"message": {
+							Type:        schema.TypeString,
+							Description: "Description of the problem.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		}, /* End schema */
+	}
+}
+
+func dataSourcemimirRuleDependenciesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*api_client)
+
+	groups, err := readRuleGroups(ctx, client)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	graph, err := buildRuleDependencyGraph(groups)
+	if err != nil {
+		return diagFromErr(err)
+	}
+
+	d.SetId(client.headers["X-Scope-OrgID"])
+	if err := d.Set("rule", flattenRuleDependencyNodes(graph.Nodes)); err != nil {
+		return diagFromErr(err)
+	}
+	if err := d.Set("problem", flattenRuleDependencyProblems(graph.Problems())); err != nil {
+		return diagFromErr(err)
+	}
+
+	return diag.Diagnostics{}
+}
+
+func flattenRuleDependencyNodes(v []ruleDependencyNode) []map[string]interface{} {
+	var nodes []map[string]interface{}
+
+	for _, v := range v {
+		node := map[string]interface{}{
+			"namespace":  v.Namespace,
+			"group":      v.Group,
+			"name":       v.Name,
+			"type":       "alerting",
+			"depends_on": v.DependsOn,
+		}
+		if v.Record {
+			node["type"] = "recording"
+		}
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
+
+func flattenRuleDependencyProblems(v []ruleDependencyProblem) []map[string]interface{} {
+	var problems []map[string]interface{}
+
+	for _, v := range v {
+		problems = append(problems, map[string]interface{}{
+			"type":      v.Type,
+			"namespace": v.Namespace,
+			"group":     v.Group,
+			"rule":      v.Rule,
+			"message":   v.Message,
+		})
+	}
+
+	return problems
+}
@@ -0,0 +1,104 @@
+package mimir
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceRuleDependencies_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMimirRuleGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceRuleDependencies_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.mimir_rule_dependencies.deps", "rule.*", map[string]string{
+						"namespace":    "namespace_1",
+						"group":        "alert_1",
+						"name":         "test1",
+						"type":         "alerting",
+						"depends_on.#": "2",
+						"depends_on.0": "job:test1:sum",
+						"depends_on.1": "job:undefined:sum",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("data.mimir_rule_dependencies.deps", "problem.*", map[string]string{
+						"type":    "order",
+						"group":   "record_1",
+						"rule":    "job:test1:rate",
+						"message": `rule "job:test1:rate" of rule group 'namespace_1/record_1' uses "job:test1:sum" recorded later in the same group`,
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("data.mimir_rule_dependencies.deps", "problem.*", map[string]string{
+						"type":  "undefined",
+						"group": "alert_1",
+						"rule":  "test1",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceRuleDependencies_check(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDataSourceRuleDependencies_check,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`uses "job:test1:sum" recorded later in the same group`),
+			},
+		},
+	})
+}
+
+const testAccDataSourceRuleDependencies_basic = `
+	resource "mimir_rule_group_recording" "record_1" {
+		name = "record_1"
+		namespace = "namespace_1"
+		rule {
+			record = "job:test1:rate"
+			expr   = "job:test1:sum / 60"
+		}
+		rule {
+			record = "job:test1:sum"
+			expr   = "sum by (job) (test1_metric)"
+		}
+	}
+
+	resource "mimir_rule_group_alerting" "alert_1" {
+		name = "alert_1"
+		namespace = "namespace_1"
+		rule {
+			alert = "test1"
+			expr  = "job:test1:sum > 1 and job:undefined:sum > 1"
+		}
+	}
+
+	data "mimir_rule_dependencies" "deps" {
+		depends_on = [mimir_rule_group_recording.record_1, mimir_rule_group_alerting.alert_1]
+	}
+`
+
+const testAccDataSourceRuleDependencies_check = `
+	provider "mimir" {
+		rule_dependencies_check = "error"
+	}
+
+	resource "mimir_rule_group_recording" "record_1" {
+		name = "record_1"
+		namespace = "namespace_1"
+		rule {
+			record = "job:test1:rate"
+			expr   = "job:test1:sum / 60"
+		}
+		rule {
+			record = "job:test1:sum"
+			expr   = "sum by (job) (test1_metric)"
+		}
+	}
+`
@@ -0,0 +1,80 @@
+package mimir
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcemimirRuleDuplicates() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcemimirRuleDuplicatesRead,
+
+		Schema: map[string]*schema.Schema{
+			"duplicate": {
+				Type:        schema.TypeList,
+				Description: "Recorded series and alerts, identified by their name and labels, defined by more than one rule group of the tenant.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:        schema.TypeString,
+							Description: "Type of the rule, `record` or `alert`.",
+							Computed:    true,
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Description: "Name of the recorded series or of the alert.",
+							Computed:    true,
+						},
+						"labels": {
+							Type:        schema.TypeMap,
+							Description: "Labels of the rule.",
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"owners": {
+							Type:        schema.TypeList,
+							Description: "Rule groups defining the rule, as `<namespace>/<name>`.",
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"message": {
+							Type:        schema.TypeString,
+							Description: "Description of the duplicate.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		}, /* End schema */
+	}
+}
+
+func dataSourcemimirRuleDuplicatesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*api_client)
+
+	groups, err := readRuleGroups(ctx, client)
+	if err != nil {
+		return diagFromErr(err)
+	}
+
+	var duplicates []map[string]interface{}
+	for _, v := range findRuleDuplicates(groups) {
+		duplicates = append(duplicates, map[string]interface{}{
+			"type":    v.Type,
+			"name":    v.Name,
+			"labels":  v.Labels,
+			"owners":  v.Owners,
+			"message": v.Message(),
+		})
+	}
+
+	d.SetId(client.headers["X-Scope-OrgID"])
+	if err := d.Set("duplicate", duplicates); err != nil {
+		return diagFromErr(err)
+	}
+
+	return diag.Diagnostics{}
+}
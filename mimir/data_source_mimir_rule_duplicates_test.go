@@ -0,0 +1,85 @@
+package mimir
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceRuleDuplicates_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMimirRuleGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceRuleDuplicates_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.mimir_rule_duplicates.duplicates", "duplicate.*", map[string]string{
+						"type":            "alert",
+						"name":            "test1",
+						"labels.severity": "critical",
+						"owners.#":        "2",
+						"owners.0":        "namespace_1/alert_1",
+						"owners.1":        "namespace_2/alert_1",
+						"message":         `alert "test1" with labels {severity="critical"} is defined by the rule groups 'namespace_1/alert_1' and 'namespace_2/alert_1'`,
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceRuleDuplicates_check(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMimirRuleGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDataSourceRuleDuplicates_check,
+				ExpectError: regexp.MustCompile(`alert "test1" with labels {severity="critical"} is defined by the rule groups`),
+			},
+		},
+	})
+}
+
+const testAccDataSourceRuleDuplicates_groups = `
+	resource "mimir_rule_group_alerting" "alert_1" {
+		name = "alert_1"
+		namespace = "namespace_1"
+		rule {
+			alert = "test1"
+			expr  = "test1_metric > 1"
+			labels = {
+				severity = "critical"
+			}
+		}
+	}
+
+	resource "mimir_rule_group_alerting" "alert_2" {
+		name = "alert_1"
+		namespace = "namespace_2"
+		rule {
+			alert = "test1"
+			expr  = "test1_metric > 2"
+			labels = {
+				severity = "critical"
+			}
+		}
+		depends_on = [mimir_rule_group_alerting.alert_1]
+	}
+`
+
+const testAccDataSourceRuleDuplicates_basic = testAccDataSourceRuleDuplicates_groups + `
+	data "mimir_rule_duplicates" "duplicates" {
+		depends_on = [mimir_rule_group_alerting.alert_1, mimir_rule_group_alerting.alert_2]
+	}
+`
+
+const testAccDataSourceRuleDuplicates_check = `
+	provider "mimir" {
+		duplicate_rules_check = "error"
+	}
+` + testAccDataSourceRuleDuplicates_groups
@@ -1,16 +1,17 @@
 package mimir
 
 import (
+	"context"
 	"fmt"
-	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"gopkg.in/yaml.v3"
 )
 
 func dataSourcemimirRuleGroupRecording() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourcemimirRuleGroupRecordingRead,
+		ReadContext: dataSourcemimirRuleGroupRecordingRead,
 
 		Schema: map[string]*schema.Schema{
 			"namespace": {
@@ -40,6 +41,11 @@ func dataSourcemimirRuleGroupRecording() *schema.Resource {
 							Type:     schema.TypeString,
 							Computed: true,
 						},
+						"labels": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
 					},
 				},
 			},
@@ -48,24 +54,24 @@ func dataSourcemimirRuleGroupRecording() *schema.Resource {
 	}
 }
 
-func dataSourcemimirRuleGroupRecordingRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourcemimirRuleGroupRecordingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*api_client)
 	name := d.Get("name").(string)
 	namespace := d.Get("namespace").(string)
 
 	var headers map[string]string
 	path := fmt.Sprintf("/config/v1/rules/%s/%s", namespace, name)
-	jobraw, err := client.send_request("ruler", "GET", path, "", headers)
+	jobraw, err := client.send_request(ctx, "ruler", "GET", path, "", headers)
 
 	baseMsg := fmt.Sprintf("Cannot read recording rule group '%s' -", name)
 	fullurl := fmt.Sprintf("%s%s", client.uri, path)
 	err = handleHTTPError(err, jobraw, fullurl, baseMsg)
 	if err != nil {
-		if strings.Contains(err.Error(), "response code '404'") {
+		if isNotFoundError(err) {
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromErr(err)
 	}
 
 	d.SetId(fmt.Sprintf("%s/%s", namespace, name))
@@ -73,10 +79,10 @@ func dataSourcemimirRuleGroupRecordingRead(d *schema.ResourceData, meta interfac
 	var data recordingRuleGroup
 	err = yaml.Unmarshal([]byte(jobraw), &data)
 	if err != nil {
-		return fmt.Errorf("Unable to decode recording rule group '%s' data: %v", name, err)
+		return diagFromErr(fmt.Errorf("Unable to decode recording rule group '%s' data: %v", name, err))
 	}
 	if err := d.Set("rule", flattenRecordingRules(data.Rules)); err != nil {
-		return err
+		return diagFromErr(err)
 	}
 
 	return nil
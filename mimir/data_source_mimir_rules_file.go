@@ -0,0 +1,299 @@
+package mimir
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v3"
+)
+
+func dataSourcemimirRulesFile() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcemimirRulesFileRead,
+
+		Schema: map[string]*schema.Schema{
+			"content": {
+				Type:        schema.TypeString,
+				Description: "Content of a Prometheus or Mimir rule file, e.g. read with the `file` function.",
+				Required:    true,
+			},
+			"namespace": {
+				Type:        schema.TypeString,
+				Description: "Namespace declared by the rule file, if any.",
+				Computed:    true,
+			},
+			"group": {
+				Type:        schema.TypeList,
+				Description: "Rule groups of the rule file.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Description: "Rule group name",
+							Computed:    true,
+						},
+						"interval": {
+							Type:        schema.TypeString,
+							Description: "How often rules in the group are evaluated.",
+							Computed:    true,
+						},
+						"limit": {
+							Type:        schema.TypeInt,
+							Description: "Limit the number of alerts an alerting rule and series a recording rule can produce.",
+							Computed:    true,
+						},
+						"source_tenants": {
+							Type:        schema.TypeList,
+							Description: "Tenants the rules of a federated rule group are evaluated against.",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Computed:    true,
+						},
+						"alerting_rule": {
+							Type:        schema.TypeList,
+							Description: "Alerting rules of the group, shaped like the `rule` blocks of `mimir_rule_group_alerting`.",
+							Computed:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"alert": {
+										Type:        schema.TypeString,
+										Description: "Alerting Rule name",
+										Computed:    true,
+									},
+									"expr": {
+										Type:        schema.TypeString,
+										Description: "Alerting Rule query",
+										Computed:    true,
+									},
+									"for": {
+										Type:        schema.TypeString,
+										Description: "Alerting Rule duration",
+										Computed:    true,
+									},
+									"annotations": {
+										Type:        schema.TypeMap,
+										Description: "Alerting Rule annotations",
+										Elem:        &schema.Schema{Type: schema.TypeString},
+										Computed:    true,
+									},
+									"labels": {
+										Type:        schema.TypeMap,
+										Description: "Alerting Rule labels",
+										Elem:        &schema.Schema{Type: schema.TypeString},
+										Computed:    true,
+									},
+								},
+							},
+						},
+						"recording_rule": {
+							Type:        schema.TypeList,
+							Description: "Recording rules of the group, shaped like the `rule` blocks of `mimir_rule_group_recording`.",
+							Computed:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"record": {
+										Type:        schema.TypeString,
+										Description: "Recording Rule name",
+										Computed:    true,
+									},
+									"expr": {
+										Type:        schema.TypeString,
+										Description: "Recording Rule query",
+										Computed:    true,
+									},
+									"labels": {
+										Type:        schema.TypeMap,
+										Description: "Recording Rule labels",
+										Elem:        &schema.Schema{Type: schema.TypeString},
+										Computed:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}, /* End schema */
+	}
+}
+
+func dataSourcemimirRulesFileRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	content := d.Get("content").(string)
+
+	data, err := parseRulesFile(content)
+	if err != nil {
+		return diag.Errorf("Unable to decode rule file: %v", err)
+	}
+
+	if diags := validateRulesFile(data); diags.HasError() {
+		return diags
+	}
+
+	d.SetId(hashString(content))
+	d.Set("namespace", data.Namespace)
+	if err := d.Set("group", flattenRulesFileGroups(data.Groups)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.Diagnostics{}
+}
+
+// parseRulesFile decodes a rule file, refusing unknown keys so that typos
+// are not silently dropped.
+func parseRulesFile(content string) (*rulesFile, error) {
+	var data rulesFile
+
+	dec := yaml.NewDecoder(bytes.NewBufferString(content))
+	dec.KnownFields(true)
+	if err := dec.Decode(&data); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func validateRulesFile(data *rulesFile) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	addErrors := func(errors []error) {
+		for _, err := range errors {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Invalid rule file",
+				Detail:   err.Error(),
+			})
+		}
+	}
+	addError := func(format string, a ...interface{}) {
+		addErrors([]error{fmt.Errorf(format, a...)})
+	}
+
+	names := make(map[string]bool)
+	for i, group := range data.Groups {
+		k := fmt.Sprintf("groups.%d", i)
+
+		_, errors := validateGroupRuleName(group.Name, k+".name")
+		addErrors(errors)
+		if names[group.Name] {
+			addError("\"%s.name\": Duplicate Group Rule Name %q", k, group.Name)
+		}
+		names[group.Name] = true
+
+		_, errors = validateDuration(group.Interval, k+".interval")
+		addErrors(errors)
+
+		if len(group.Rules) == 0 {
+			addError("\"%s.rules\": Group Rule %q has no rules", k, group.Name)
+		}
+
+		for j, rule := range group.Rules {
+			k := fmt.Sprintf("%s.rules.%d", k, j)
+
+			switch {
+			case rule.Alert != "" && rule.Record != "":
+				addError("\"%s\": only one of alert and record can be set", k)
+				continue
+			case rule.Alert != "":
+				_, errors = validateAlertingRuleName(rule.Alert, k+".alert")
+				addErrors(errors)
+				_, errors = validateDuration(rule.For, k+".for")
+				addErrors(errors)
+				_, errors = validateAnnotations(toInterfaceMap(rule.Annotations), k+".annotations")
+				addErrors(errors)
+			case rule.Record != "":
+				_, errors = validateRecordingRuleName(rule.Record, k+".record")
+				addErrors(errors)
+				if rule.For != "" || len(rule.Annotations) > 0 {
+					addError("\"%s\": for and annotations cannot be set on recording rule %q", k, rule.Record)
+				}
+			default:
+				addError("\"%s\": one of alert or record must be set", k)
+				continue
+			}
+
+			_, errors = validatePromQLExpr(rule.Expr, k+".expr")
+			addErrors(errors)
+			_, errors = validateLabels(toInterfaceMap(rule.Labels), k+".labels")
+			addErrors(errors)
+		}
+	}
+
+	return diags
+}
+
+func flattenRulesFileGroups(v []rulesFileGroup) []map[string]interface{} {
+	var groups []map[string]interface{}
+
+	for _, v := range v {
+		group := make(map[string]interface{})
+		group["name"] = v.Name
+		group["interval"] = v.Interval
+		group["limit"] = v.Limit
+		group["source_tenants"] = v.SourceTenants
+
+		var alertingRules []alertingRule
+		var recordingRules []map[string]interface{}
+		for _, r := range v.Rules {
+			if r.Alert != "" {
+				alertingRules = append(alertingRules, alertingRule{
+					Alert:       r.Alert,
+					Expr:        r.Expr,
+					For:         r.For,
+					Labels:      r.Labels,
+					Annotations: r.Annotations,
+				})
+				continue
+			}
+
+			rule := make(map[string]interface{})
+			rule["record"] = r.Record
+			rule["expr"] = r.Expr
+			if r.Labels != nil {
+				rule["labels"] = r.Labels
+			}
+			recordingRules = append(recordingRules, rule)
+		}
+		group["alerting_rule"] = flattenAlertingRules(alertingRules)
+		group["recording_rule"] = recordingRules
+
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// String Map to Map
+func toInterfaceMap(v map[string]string) map[string]interface{} {
+	m := make(map[string]interface{})
+	for key, val := range v {
+		m[key] = val
+	}
+
+	return m
+}
+
+type rulesFileRule struct {
+	Record      string            `yaml:"record,omitempty"`
+	Alert       string            `yaml:"alert,omitempty"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+type rulesFileGroup struct {
+	Name          string          `yaml:"name"`
+	Interval      string          `yaml:"interval,omitempty"`
+	Limit         int             `yaml:"limit,omitempty"`
+	SourceTenants []string        `yaml:"source_tenants,omitempty"`
+	Rules         []rulesFileRule `yaml:"rules"`
+}
+
+type rulesFile struct {
+	Namespace string           `yaml:"namespace,omitempty"`
+	Groups    []rulesFileGroup `yaml:"groups"`
+}
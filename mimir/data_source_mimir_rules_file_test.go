@@ -0,0 +1,115 @@
+package mimir
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceRulesFile_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceRulesFile_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mimir_rules_file.rules", "namespace", "infra"),
+					resource.TestCheckResourceAttr("data.mimir_rules_file.rules", "group.#", "1"),
+					resource.TestCheckResourceAttr("data.mimir_rules_file.rules", "group.0.name", "node"),
+					resource.TestCheckResourceAttr("data.mimir_rules_file.rules", "group.0.interval", "1m"),
+					resource.TestCheckResourceAttr("data.mimir_rules_file.rules", "group.0.recording_rule.#", "1"),
+					resource.TestCheckResourceAttr("data.mimir_rules_file.rules", "group.0.recording_rule.0.record", "instance:up:sum"),
+					resource.TestCheckResourceAttr("data.mimir_rules_file.rules", "group.0.recording_rule.0.expr", "sum by (instance) (up)"),
+					resource.TestCheckResourceAttr("data.mimir_rules_file.rules", "group.0.alerting_rule.#", "1"),
+					resource.TestCheckResourceAttr("data.mimir_rules_file.rules", "group.0.alerting_rule.0.alert", "InstanceDown"),
+					resource.TestCheckResourceAttr("data.mimir_rules_file.rules", "group.0.alerting_rule.0.for", "5m"),
+					resource.TestCheckResourceAttr("data.mimir_rules_file.rules", "group.0.alerting_rule.0.labels.severity", "critical"),
+					resource.TestCheckResourceAttr("data.mimir_rules_file.rules", "group.0.alerting_rule.0.annotations.summary", "Instance is down"),
+					resource.TestCheckResourceAttr("mimir_rule_group_alerting.alert_1", "rule.#", "1"),
+					resource.TestCheckResourceAttr("mimir_rule_group_alerting.alert_1", "rule.0.alert", "InstanceDown"),
+					resource.TestCheckResourceAttr("mimir_rule_group_alerting.alert_1", "rule.0.expr", "instance:up:sum == 0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceRulesFile_invalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDataSourceRulesFile_invalidExpr,
+				ExpectError: regexp.MustCompile(`"groups.0.rules.0.expr": Invalid PromQL expression`),
+			},
+			{
+				Config:      testAccDataSourceRulesFile_unknownKey,
+				ExpectError: regexp.MustCompile(`field expresion not found`),
+			},
+		},
+	})
+}
+
+const testAccDataSourceRulesFile_basic = `
+	data "mimir_rules_file" "rules" {
+		content = <<-EOT
+			namespace: infra
+			groups:
+			  - name: node
+			    interval: 1m
+			    rules:
+			      - record: instance:up:sum
+			        expr: sum by (instance) (up)
+			      - alert: InstanceDown
+			        expr: instance:up:sum == 0
+			        for: 5m
+			        labels:
+			          severity: critical
+			        annotations:
+			          summary: Instance is down
+		EOT
+	}
+
+	resource "mimir_rule_group_alerting" "alert_1" {
+		name      = "alert_1_from_file"
+		namespace = data.mimir_rules_file.rules.namespace
+
+		dynamic "rule" {
+			for_each = data.mimir_rules_file.rules.group[0].alerting_rule
+			content {
+				alert       = rule.value.alert
+				expr        = rule.value.expr
+				for         = rule.value.for
+				labels      = rule.value.labels
+				annotations = rule.value.annotations
+			}
+		}
+	}
+`
+
+const testAccDataSourceRulesFile_invalidExpr = `
+	data "mimir_rules_file" "rules" {
+		content = <<-EOT
+			groups:
+			  - name: node
+			    rules:
+			      - record: instance:up:sum
+			        expr: sum by (instance (up)
+		EOT
+	}
+`
+
+const testAccDataSourceRulesFile_unknownKey = `
+	data "mimir_rules_file" "rules" {
+		content = <<-EOT
+			groups:
+			  - name: node
+			    rules:
+			      - record: instance:up:sum
+			        expresion: sum by (instance) (up)
+		EOT
+	}
+`
@@ -0,0 +1,577 @@
+package mimir
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var exportNameRegexp = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// mapFlag collects the repeated name=value flags, e.g. -header.
+type mapFlag map[string]interface{}
+
+func (m mapFlag) String() string {
+	return fmt.Sprint(map[string]interface{}(m))
+}
+
+func (m mapFlag) Set(v string) error {
+	kv := strings.SplitN(v, "=", 2)
+	if len(kv) != 2 {
+		return fmt.Errorf("%q must be in the form name=value", v)
+	}
+	m[kv[0]] = kv[1]
+	return nil
+}
+
+// listFlag collects the repeated flags, e.g. -oauth2-scope.
+type listFlag []interface{}
+
+func (l *listFlag) String() string {
+	return fmt.Sprint([]interface{}(*l))
+}
+
+func (l *listFlag) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+// exportBlockFlagNames are the flag names of the repeatable block settings,
+// which take one value per flag.
+var exportBlockFlagNames = map[string]string{
+	"headers":         "header",
+	"scopes":          "scope",
+	"endpoint_params": "endpoint-param",
+}
+
+// exportBlockFlag is the provider block setting set by a flag.
+type exportBlockFlag struct {
+	setting string
+	value   func() interface{}
+}
+
+// exportBlockFlags registers the flags of the settings of a provider block,
+// named -<block>-<setting>, and returns the settings by flag name.
+func exportBlockFlags(fs *flag.FlagSet, block string, settings map[string]string) map[string]exportBlockFlag {
+	flags := make(map[string]exportBlockFlag)
+	for name, usage := range settings {
+		flagName, ok := exportBlockFlagNames[name]
+		if !ok {
+			flagName = strings.ReplaceAll(name, "_", "-")
+		}
+		flagName = fmt.Sprintf("%s-%s", block, flagName)
+		var value func() interface{}
+		switch name {
+		case "insecure":
+			v := fs.Bool(flagName, false, usage)
+			value = func() interface{} { return *v }
+		case "timeout":
+			v := fs.Int(flagName, 0, usage)
+			value = func() interface{} { return *v }
+		case "headers", "endpoint_params":
+			v := make(mapFlag)
+			fs.Var(v, flagName, usage)
+			value = func() interface{} { return map[string]interface{}(v) }
+		case "scopes":
+			v := &listFlag{}
+			fs.Var(v, flagName, usage)
+			value = func() interface{} { return []interface{}(*v) }
+		default:
+			v := fs.String(flagName, "", usage)
+			value = func() interface{} { return *v }
+		}
+		flags[flagName] = exportBlockFlag{setting: name, value: value}
+	}
+	return flags
+}
+
+// Export writes the Terraform configuration and the import blocks of the rule
+// groups and the alertmanager config of a tenant. It takes the provider
+// options as flags, falling back on the same environment variables as the
+// provider.
+func Export(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: terraform-provider-mimir export [options]\n\n")
+		fmt.Fprintf(stderr, "Print the Terraform configuration and import blocks of the rule groups and\nthe alertmanager config of a tenant.\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+
+	provider := Provider()
+	options := map[string]string{
+		"uri":              "mimir base url",
+		"ruler_uri":        "mimir ruler base url",
+		"alertmanager_uri": "mimir alertmanager base url",
+		"org_id":           "The organization id to operate on within mimir.",
+		"token":            "Token for Bearer auth to the API.",
+		"username":         "Username for BASIC auth to the API.",
+		"password":         "Password for BASIC auth to the API.",
+		"cert":             "Client cert for client authentication",
+		"key":              "Client key for client authentication",
+		"ca":               "Client ca for client authentication",
+	}
+	values := make(map[string]*string)
+	for name, usage := range options {
+		values[name] = fs.String(strings.ReplaceAll(name, "_", "-"), "", usage)
+	}
+	insecure := fs.Bool("insecure", false, "When using https, this disables TLS verification of the host.")
+	timeout := fs.Int("timeout", 60, "Requests taking longer than this time (in seconds) are aborted.")
+	debug := fs.Bool("debug", false, "Enable debug mode to trace requests executed.")
+	headers := make(mapFlag)
+	fs.Var(headers, "header", "Header to set on all outbound requests, as name=value. Can be repeated.")
+
+	// The settings of the provider blocks, e.g. -ruler-token for the token
+	// of the ruler block.
+	endpointSettings := map[string]string{
+		"token":    "Token for Bearer auth to the %s API.",
+		"username": "Username for BASIC auth to the %s API.",
+		"password": "Password for BASIC auth to the %s API.",
+		"cert":     "Client cert for client authentication to the %s API",
+		"key":      "Client key for client authentication to the %s API",
+		"ca":       "Client ca for client authentication to the %s API",
+		"insecure": "When using https, this disables TLS verification of the %s host.",
+		"timeout":  "Requests to the %s API taking longer than this time (in seconds) are aborted.",
+		"headers":  "Header to set on the %s requests, as name=value. Can be repeated.",
+	}
+	blocks := make(map[string]map[string]exportBlockFlag)
+	for _, block := range []string{"ruler", "alertmanager"} {
+		settings := make(map[string]string)
+		for name, usage := range endpointSettings {
+			settings[name] = fmt.Sprintf(usage, block)
+		}
+		blocks[block] = exportBlockFlags(fs, block, settings)
+	}
+	blocks["oauth2"] = exportBlockFlags(fs, "oauth2", map[string]string{
+		"token_url":       "URL of the OAuth2 token endpoint.",
+		"client_id":       "OAuth2 client ID.",
+		"client_secret":   "OAuth2 client secret. Defaults to the MIMIR_OAUTH2_CLIENT_SECRET environment variable.",
+		"scopes":          "OAuth2 scope to request. Can be repeated.",
+		"endpoint_params": "Additional parameter of the token requests, as name=value. Can be repeated.",
+		"cert":            "Client cert for client authentication to the token endpoint",
+		"key":             "Client key for client authentication to the token endpoint",
+		"ca":              "Client ca for client authentication to the token endpoint",
+		"insecure":        "When using https, this disables TLS verification of the token endpoint.",
+	})
+	skipRules := fs.Bool("skip-rules", false, "Do not export the rule groups.")
+	skipAlertmanager := fs.Bool("skip-alertmanager", false, "Do not export the alertmanager config.")
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+
+	// Only pass the options set on the command line, so that the provider
+	// falls back on its environment variables for the others.
+	config := make(map[string]interface{})
+	fs.Visit(func(f *flag.Flag) {
+		name := strings.ReplaceAll(f.Name, "-", "_")
+		switch name {
+		case "insecure":
+			config[name] = *insecure
+		case "timeout":
+			config[name] = *timeout
+		case "debug":
+			config[name] = *debug
+		case "header":
+			config["headers"] = map[string]interface{}(headers)
+		default:
+			if v, ok := values[name]; ok {
+				config[name] = *v
+				return
+			}
+			for block, settings := range blocks {
+				if v, ok := settings[f.Name]; ok {
+					setExportBlockSetting(config, block, v.setting, v.value())
+				}
+			}
+		}
+	})
+	if _, ok := config["oauth2"]; ok {
+		if secret := os.Getenv("MIMIR_OAUTH2_CLIENT_SECRET"); secret != "" {
+			if _, ok := config["oauth2"].([]interface{})[0].(map[string]interface{})["client_secret"]; !ok {
+				setExportBlockSetting(config, "oauth2", "client_secret", secret)
+			}
+		}
+	}
+
+	ctx := context.Background()
+	if diags := provider.Configure(ctx, terraform.NewResourceConfigRaw(config)); diags.HasError() {
+		return fmt.Errorf("Cannot configure provider: %s", diags[0].Summary)
+	}
+	client := provider.Meta().(*api_client)
+
+	e := &exporter{
+		provider: provider,
+		client:   client,
+		file:     hclwrite.NewEmptyFile(),
+		names:    make(map[string]bool),
+		stderr:   stderr,
+	}
+
+	if !*skipRules {
+		if err := e.exportRuleGroups(ctx); err != nil {
+			return err
+		}
+	}
+	if !*skipAlertmanager {
+		if err := e.exportAlertmanagerConfig(ctx); err != nil {
+			return err
+		}
+	}
+
+	e.writeVariables()
+
+	_, err := stdout.Write(e.file.Bytes())
+	return err
+}
+
+// setExportBlockSetting sets a setting of a provider block in the provider
+// config.
+func setExportBlockSetting(config map[string]interface{}, block, name string, value interface{}) {
+	if _, ok := config[block]; !ok {
+		config[block] = []interface{}{make(map[string]interface{})}
+	}
+	config[block].([]interface{})[0].(map[string]interface{})[name] = value
+}
+
+type exporter struct {
+	provider  *schema.Provider
+	client    *api_client
+	file      *hclwrite.File
+	names     map[string]bool
+	variables []string
+	stderr    io.Writer
+}
+
+func (e *exporter) exportRuleGroups(ctx context.Context) error {
+	data, err := readRuleGroups(ctx, e.client)
+	if err != nil {
+		return err
+	}
+
+	namespaces := make([]string, 0, len(data))
+	for namespace := range data {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	for _, namespace := range namespaces {
+		for _, group := range data[namespace] {
+			if len(group.Rules) == 0 {
+				fmt.Fprintf(e.stderr, "Skipping rule group '%s' of namespace '%s': it has no rule\n", group.Name, namespace)
+				continue
+			}
+			// The group would be written back without these fields.
+			if unsupported := unsupportedRuleGroupFields(group); len(unsupported) > 0 {
+				fmt.Fprintf(e.stderr, "Skipping rule group '%s' of namespace '%s': the rule group resources do not support its %s\n", group.Name, namespace, strings.Join(unsupported, ", "))
+				continue
+			}
+
+			var alertingRules []alertingRule
+			var recordingRules []recordingRule
+			for _, r := range group.Rules {
+				if r.Alert != "" {
+					alertingRules = append(alertingRules, alertingRule{
+						Alert:       r.Alert,
+						Expr:        r.Expr,
+						For:         r.For,
+						Labels:      r.Labels,
+						Annotations: r.Annotations,
+					})
+				} else {
+					recordingRules = append(recordingRules, recordingRule{
+						Record: r.Record,
+						Expr:   r.Expr,
+						Labels: r.Labels,
+					})
+				}
+			}
+
+			var typ string
+			var rules []map[string]interface{}
+			switch {
+			case len(alertingRules) > 0 && len(recordingRules) > 0:
+				fmt.Fprintf(e.stderr, "Skipping rule group '%s' of namespace '%s': it mixes alerting and recording rules\n", group.Name, namespace)
+				continue
+			case len(alertingRules) > 0:
+				typ = "mimir_rule_group_alerting"
+				rules = flattenAlertingRules(alertingRules)
+			default:
+				typ = "mimir_rule_group_recording"
+				rules = flattenRecordingRules(recordingRules)
+			}
+
+			d := e.provider.ResourcesMap[typ].Data(nil)
+			if typ == "mimir_rule_group_alerting" {
+				removeAlertingRuleDefaults(d, rules, alertingRuleDefaults{
+					Namespace:   namespace,
+					Group:       group.Name,
+					Labels:      e.client.default_alert_labels,
+					Annotations: e.client.default_alert_annotations,
+				})
+			}
+			d.Set("namespace", namespace)
+			d.Set("name", group.Name)
+			if err := d.Set("rule", rules); err != nil {
+				return err
+			}
+
+			e.writeResource(typ, fmt.Sprintf("%s_%s", namespace, group.Name), fmt.Sprintf("%s/%s", namespace, group.Name), d)
+		}
+	}
+
+	return nil
+}
+
+// unsupportedRuleGroupFields returns the fields of a rule group which the
+// rule group resources cannot manage.
+func unsupportedRuleGroupFields(group rulesFileGroup) []string {
+	var fields []string
+	if group.Interval != "" {
+		fields = append(fields, "interval")
+	}
+	if group.Limit != 0 {
+		fields = append(fields, "limit")
+	}
+	if len(group.SourceTenants) > 0 {
+		fields = append(fields, "source_tenants")
+	}
+	return fields
+}
+
+func (e *exporter) exportAlertmanagerConfig(ctx context.Context) error {
+	typ := "mimir_alertmanager_config"
+	r := e.provider.ResourcesMap[typ]
+
+	id := e.client.headers["X-Scope-OrgID"]
+	d := r.Data(&terraform.InstanceState{ID: id})
+	if diags := r.ReadContext(ctx, d, e.client); diags.HasError() {
+		return fmt.Errorf("%s", diags[0].Summary)
+	}
+	// The tenant has no alertmanager config.
+	if d.Id() == "" {
+		return nil
+	}
+
+	e.writeResource(typ, id, id, d)
+	return nil
+}
+
+// writeResource appends the resource block and its import block.
+func (e *exporter) writeResource(typ, name, id string, d *schema.ResourceData) {
+	name = e.resourceName(name)
+	body := e.file.Body()
+
+	values := make(map[string]interface{})
+	for k := range e.provider.ResourcesMap[typ].Schema {
+		values[k] = d.Get(k)
+	}
+	block := body.AppendNewBlock("resource", []string{typ, name})
+	e.writeBody(block.Body(), e.provider.ResourcesMap[typ].Schema, values, []string{name})
+	body.AppendNewline()
+
+	block = body.AppendNewBlock("import", nil)
+	block.Body().SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: typ},
+		hcl.TraverseAttr{Name: name},
+	})
+	block.Body().SetAttributeValue("id", cty.StringVal(id))
+	body.AppendNewline()
+}
+
+// writeVariables appends the variable blocks of the masked secrets.
+func (e *exporter) writeVariables() {
+	body := e.file.Body()
+	for _, name := range e.variables {
+		block := body.AppendNewBlock("variable", []string{name})
+		block.Body().SetAttributeTraversal("type", hcl.Traversal{hcl.TraverseRoot{Name: "string"}})
+		block.Body().SetAttributeValue("sensitive", cty.True)
+		body.AppendNewline()
+	}
+}
+
+// secretVariable returns a reference to a new variable for a secret masked
+// by the API.
+func (e *exporter) secretVariable(path []string) hcl.Traversal {
+	name := e.resourceName(strings.Join(path, "_"))
+	e.variables = append(e.variables, name)
+	fmt.Fprintf(e.stderr, "The value of %s is masked by the API: set it with the variable %s\n", strings.Join(path, "."), name)
+
+	return hcl.Traversal{
+		hcl.TraverseRoot{Name: "var"},
+		hcl.TraverseAttr{Name: name},
+	}
+}
+
+// resourceName returns a unique and valid resource name.
+func (e *exporter) resourceName(v string) string {
+	name := exportNameRegexp.ReplaceAllString(v, "_")
+	if !labelNameRegexp.MatchString(name[:1]) {
+		name = "_" + name
+	}
+
+	unique := name
+	for i := 2; e.names[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	e.names[unique] = true
+
+	return unique
+}
+
+// writeBody writes the attributes and the blocks of a resource body from its
+// schema, leaving out the computed attributes and the unset or default values.
+// Masked secrets are replaced by variables, or left out with a warning when
+// they are part of a list or map.
+func (e *exporter) writeBody(body *hclwrite.Body, s map[string]*schema.Schema, values map[string]interface{}, path []string) {
+	var attributes, blocks []string
+	for k, v := range s {
+		if (v.Computed && !v.Optional) || v.Deprecated != "" {
+			continue
+		}
+		if _, ok := v.Elem.(*schema.Resource); ok {
+			blocks = append(blocks, k)
+		} else {
+			attributes = append(attributes, k)
+		}
+	}
+	sort.Strings(attributes)
+	sort.Strings(blocks)
+
+	for _, k := range attributes {
+		value := values[k]
+		if set, ok := value.(*schema.Set); ok {
+			value = set.List()
+		}
+		if isExportDefault(s[k], value) {
+			continue
+		}
+		attrPath := append(append([]string{}, path...), k)
+		if v, ok := value.(string); ok && isMaskedSecret(v) {
+			body.SetAttributeTraversal(k, e.secretVariable(attrPath))
+			continue
+		}
+		if hasExportMaskedSecret(value) {
+			fmt.Fprintf(e.stderr, "Leaving out %s: it contains values masked by the API\n", strings.Join(attrPath, "."))
+			continue
+		}
+		body.SetAttributeValue(k, exportValue(value))
+	}
+
+	for _, k := range blocks {
+		value := values[k]
+		if set, ok := value.(*schema.Set); ok {
+			value = set.List()
+		}
+		items, _ := value.([]interface{})
+		for i, item := range items {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			// Name the variables of the named blocks, e.g. the receivers,
+			// after them.
+			blockPath := append(append([]string{}, path...), k)
+			if name, ok := m["name"].(string); ok && name != "" {
+				blockPath = append(blockPath, name)
+			} else if len(items) > 1 {
+				blockPath = append(blockPath, fmt.Sprint(i))
+			}
+			block := body.AppendNewBlock(k, nil)
+			e.writeBody(block.Body(), s[k].Elem.(*schema.Resource).Schema, m, blockPath)
+		}
+	}
+}
+
+// hasExportMaskedSecret returns whether a list or map value contains a
+// masked secret.
+func hasExportMaskedSecret(v interface{}) bool {
+	switch v := v.(type) {
+	case string:
+		return isMaskedSecret(v)
+	case []interface{}:
+		for _, item := range v {
+			if hasExportMaskedSecret(item) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			if hasExportMaskedSecret(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isExportDefault(s *schema.Schema, v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	if s.Default != nil && reflect.DeepEqual(s.Default, v) {
+		return true
+	}
+
+	switch v := v.(type) {
+	case bool:
+		return !v && s.Default == nil
+	case string:
+		return v == ""
+	case int:
+		return v == 0
+	case float64:
+		return v == 0
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+
+	return false
+}
+
+func exportValue(v interface{}) cty.Value {
+	switch v := v.(type) {
+	case bool:
+		return cty.BoolVal(v)
+	case string:
+		return cty.StringVal(v)
+	case int:
+		return cty.NumberIntVal(int64(v))
+	case float64:
+		return cty.NumberFloatVal(v)
+	case []interface{}:
+		if len(v) == 0 {
+			return cty.ListValEmpty(cty.String)
+		}
+		var values []cty.Value
+		for _, item := range v {
+			values = append(values, exportValue(item))
+		}
+		return cty.TupleVal(values)
+	case map[string]interface{}:
+		values := make(map[string]cty.Value)
+		for key, item := range v {
+			values[key] = exportValue(item)
+		}
+		return cty.ObjectVal(values)
+	}
+
+	return cty.StringVal(fmt.Sprint(v))
+}
@@ -0,0 +1,206 @@
+package mimir
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccExport_RuleGroups(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMimirRuleGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccExport_ruleGroups,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExportContains(`resource "mimir_rule_group_alerting" "export_1_alert_1" {`),
+					testAccCheckExportContains(`resource "mimir_rule_group_recording" "export_1_record_1" {`),
+					testAccCheckExportContains(`
+import {
+  to = mimir_rule_group_alerting.export_1_alert_1
+  id = "export_1/alert_1"
+}
+`),
+					testAccCheckExportContains(`    alert = "test1"
+    expr  = "test1_metric"
+    for   = "1m"
+`),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckExportContains(expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		var out bytes.Buffer
+		if err := Export([]string{"-skip-alertmanager"}, &out, io.Discard); err != nil {
+			return err
+		}
+		if !strings.Contains(out.String(), expected) {
+			return fmt.Errorf("export output does not contain:\n%s\ngot:\n%s", expected, out.String())
+		}
+		return nil
+	}
+}
+
+const testAccExport_ruleGroups = `
+	resource "mimir_rule_group_alerting" "alert_1" {
+		name      = "alert_1"
+		namespace = "export_1"
+		rule {
+			alert = "test1"
+			expr  = "test1_metric"
+			for   = "1m"
+		}
+	}
+
+	resource "mimir_rule_group_recording" "record_1" {
+		name      = "record_1"
+		namespace = "export_1"
+		rule {
+			record = "test1_info"
+			expr   = "test1_metric"
+		}
+	}
+`
+
+func TestExporterWriteBody(t *testing.T) {
+	s := map[string]*schema.Schema{
+		"name": {Type: schema.TypeString, Required: true},
+		"receiver": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name":    {Type: schema.TypeString, Required: true},
+					"url":     {Type: schema.TypeString, Optional: true},
+					"headers": {Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+				},
+			},
+		},
+	}
+	values := map[string]interface{}{
+		"name": "tenant",
+		"receiver": []interface{}{
+			map[string]interface{}{
+				"name":    "team-a",
+				"url":     "<secret>",
+				"headers": map[string]interface{}{"Authorization": "<secret>"},
+			},
+		},
+	}
+
+	var stderr bytes.Buffer
+	e := &exporter{
+		file:   hclwrite.NewEmptyFile(),
+		names:  make(map[string]bool),
+		stderr: &stderr,
+	}
+	block := e.file.Body().AppendNewBlock("resource", []string{"test", "tenant"})
+	e.writeBody(block.Body(), s, values, []string{"tenant"})
+	e.writeVariables()
+
+	expected := `resource "test" "tenant" {
+  name = "tenant"
+  receiver {
+    name = "team-a"
+    url  = var.tenant_receiver_team-a_url
+  }
+}
+variable "tenant_receiver_team-a_url" {
+  type      = string
+  sensitive = true
+}
+
+`
+	if got := string(e.file.Bytes()); got != expected {
+		t.Fatalf("Got:\n%s\nbut expected:\n%s", got, expected)
+	}
+	if !strings.Contains(stderr.String(), "Leaving out tenant.receiver.team-a.headers") {
+		t.Fatalf("Expected a warning for the masked header, got: %s", stderr.String())
+	}
+}
+
+func TestUnsupportedRuleGroupFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		group    rulesFileGroup
+		expected []string
+	}{
+		{
+			name:  "supported",
+			group: rulesFileGroup{Name: "a", Rules: []rulesFileRule{{Alert: "A", Expr: "up == 0", Labels: map[string]string{"severity": "page"}}}},
+		},
+		{
+			name: "unsupported",
+			group: rulesFileGroup{
+				Name:          "a",
+				Interval:      "1m",
+				Limit:         10,
+				SourceTenants: []string{"tenant"},
+				Rules:         []rulesFileRule{{Record: "job:up:sum", Expr: "sum by (job) (up)", Labels: map[string]string{"team": "a"}}},
+			},
+			expected: []string{"interval", "limit", "source_tenants"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unsupportedRuleGroupFields(tt.group); !reflect.DeepEqual(got, tt.expected) {
+				t.Fatalf("Got %v but expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExportFlags(t *testing.T) {
+	var authorization, header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"access-token","token_type":"Bearer","expires_in":3600}`))
+		case "/prometheus/config/v1/rules":
+			authorization = r.Header.Get("Authorization")
+			header = r.Header.Get("X-Test")
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusTeapot)
+		}
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	err := Export([]string{
+		"-skip-alertmanager",
+		"-org-id", "tenant",
+		"-ruler-uri", server.URL + "/prometheus",
+		"-ruler-header", "X-Test=ruler",
+		"-oauth2-token-url", server.URL + "/token",
+		"-oauth2-client-id", "mimir",
+		"-oauth2-scope", "rules",
+	}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("%v: %s", err, stderr.String())
+	}
+
+	if authorization != "Bearer access-token" {
+		t.Errorf("Got Authorization header %q but expected the oauth2 access token", authorization)
+	}
+	if header != "ruler" {
+		t.Errorf("Got X-Test header %q but expected the ruler header", header)
+	}
+}
@@ -2,6 +2,9 @@ package mimir
 
 import (
 	"context"
+	"fmt"
+	"time"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -28,6 +31,67 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("MIMIR_ALERTMANAGER_URI", nil),
 				Description: "mimir alertmanager base url",
 			},
+			"ruler":        apiEndpointSchema("ruler"),
+			"alertmanager": apiEndpointSchema("alertmanager"),
+			"oauth2": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Authenticate with OAuth2 access tokens obtained with the client credentials grant and refreshed before they expire. They replace the `token` and basic auth, except for the `ruler` and `alertmanager` blocks setting their own.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"token_url": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "URL of the token endpoint of the identity provider.",
+							ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+						},
+						"client_id": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "OAuth2 client ID.",
+						},
+						"client_secret": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Sensitive:   true,
+							Description: "OAuth2 client secret.",
+						},
+						"scopes": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "Scopes requested for the access tokens.",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"endpoint_params": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Description: "Additional parameters of the token requests, e.g. `{ audience = \"mimir\" }`.",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"insecure": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "When using https, this disables TLS verification of the token endpoint.",
+						},
+						"cert": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Client cert for client authentication to the token endpoint",
+						},
+						"key": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Client key for client authentication to the token endpoint",
+						},
+						"ca": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Client ca for client authentication to the token endpoint",
+						},
+					},
+				},
+			},
 			"org_id": {
 				Type:         schema.TypeString,
 				Required:     true,
@@ -86,22 +150,98 @@ func Provider() *schema.Provider {
 				Default:     60,
 				Description: "When set, will cause requests taking longer than this time (in seconds) to be aborted.",
 			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				Description:  "Maximum number of requests in flight, to stay under the API limits of the tenant. Unlimited when 0.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"requests_per_second": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      0,
+				Description:  "Maximum rate of the requests, retries included, to stay under the API limits of the tenant. Unlimited when 0.",
+				ValidateFunc: validation.FloatAtLeast(0),
+			},
+			"retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("MIMIR_RETRIES", 3),
+				Description:  "Number of times a request failing with a network error, a 429 or a 5xx response is retried. Only the idempotent requests and the ruler and alertmanager POSTs, which overwrite whole objects, are retried.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"retry_wait_min": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				Description:  "Minimum time (in seconds) to wait before retrying a request. The wait doubles on every retry, with jitter.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"retry_wait_max": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      30,
+				Description:  "Maximum time (in seconds) to wait before retrying a request. The `Retry-After` header of the response takes precedence.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
 			"debug": {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("MIMIR_DEBUG", false),
 				Description: "Enable debug mode to trace requests executed.",
 			},
+			"enforced_matchers": {
+				Type:         schema.TypeMap,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				Optional:     true,
+				Description:  "Label matchers enforced on every vector selector of the rule expressions before they are sent to the ruler, e.g. `{ cluster = \"eu-1\" }`. Any matcher on the same label is replaced.",
+				ValidateFunc: validateLabels,
+			},
+			"default_alert_labels": {
+				Type:         schema.TypeMap,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				Optional:     true,
+				Description:  "Labels merged into every alerting rule, the labels of the rule winning. Values are templates with `[[ ]]` delimiters, where `.Namespace`, `.Group` and `.Alert` are available.",
+				ValidateFunc: validateDefaultAlertLabels,
+			},
+			"default_alert_annotations": {
+				Type:         schema.TypeMap,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				Optional:     true,
+				Description:  "Annotations merged into every alerting rule, the annotations of the rule winning. Values are templates with `[[ ]]` delimiters, where `.Namespace`, `.Group` and `.Alert` are available.",
+				ValidateFunc: validateDefaultAlertAnnotations,
+			},
+			"alert_policy": alertPolicySchema(),
+			"rule_dependencies_check": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Check the dependencies of the rule groups on the recorded metrics of the tenant: uses of undefined recorded metrics, of metrics recorded later in the same group and cycles. Whether the problems are errors (`error`) or warnings (`warning`).",
+				ValidateFunc: validation.StringInSlice([]string{alertPolicyLevelError, alertPolicyLevelWarning}, false),
+			},
+			"duplicate_rules_check": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Check that the recorded series and the alerts, identified by their name and labels, of the rule groups are not defined by other rule groups of the tenant. Whether the duplicates are errors (`error`) or warnings (`warning`).",
+				ValidateFunc: validation.StringInSlice([]string{alertPolicyLevelError, alertPolicyLevelWarning}, false),
+			},
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"mimir_alertmanager_config":  dataSourcemimirAlertmanagerConfig(),
-			"mimir_rule_group_alerting":  dataSourcemimirRuleGroupAlerting(),
-			"mimir_rule_group_recording": dataSourcemimirRuleGroupRecording(),
+			"mimir_alertmanager_config":          dataSourcemimirAlertmanagerConfig(),
+			"mimir_alertmanager_template":        dataSourcemimirAlertmanagerTemplate(),
+			"mimir_alertmanager_config_document": dataSourcemimirAlertmanagerConfigDocument(),
+			"mimir_rule_group_alerting":          dataSourcemimirRuleGroupAlerting(),
+			"mimir_rule_group_recording":         dataSourcemimirRuleGroupRecording(),
+			"mimir_rules_file":                   dataSourcemimirRulesFile(),
+			"mimir_rule_dependencies":            dataSourcemimirRuleDependencies(),
+			"mimir_rule_duplicates":              dataSourcemimirRuleDuplicates(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"mimir_alertmanager_config":  resourcemimirAlertmanagerConfig(),
 			"mimir_rule_group_alerting":  resourcemimirRuleGroupAlerting(),
 			"mimir_rule_group_recording": resourcemimirRuleGroupRecording(),
+			"mimir_rules_sync":           resourcemimirRulesSync(),
+			"mimir_slo":                  resourcemimirSLO(),
 		},
 	}
 	p.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
@@ -134,8 +274,137 @@ func providerConfigure(d *schema.ResourceData, terraformVersion string) (interfa
 		headers:          headers,
 		timeout:          d.Get("timeout").(int),
 		debug:            d.Get("debug").(bool),
+		ruler:            expandAPIEndpointOpt(d, "ruler"),
+		alertmanager:     expandAPIEndpointOpt(d, "alertmanager"),
+		oauth2:           expandAPIOAuth2Opt(d.Get("oauth2").([]interface{})),
+		retries:          d.Get("retries").(int),
+		retry_wait_min:   time.Duration(d.Get("retry_wait_min").(int)) * time.Second,
+		retry_wait_max:   time.Duration(d.Get("retry_wait_max").(int)) * time.Second,
+
+		max_concurrent_requests: d.Get("max_concurrent_requests").(int),
+		requests_per_second:     d.Get("requests_per_second").(float64),
+
+		enforced_matchers:         expandStringMap(d.Get("enforced_matchers").(map[string]interface{})),
+		default_alert_labels:      expandStringMap(d.Get("default_alert_labels").(map[string]interface{})),
+		default_alert_annotations: expandStringMap(d.Get("default_alert_annotations").(map[string]interface{})),
+		alert_policy:              expandAlertPolicy(d.Get("alert_policy").([]interface{})),
+		rule_dependencies_check:   d.Get("rule_dependencies_check").(string),
+		duplicate_rules_check:     d.Get("duplicate_rules_check").(string),
 	}
 
 	client, err := NewAPIClient(opt)
 	return client, diag.FromErr(err)
 }
+
+// apiEndpointSchema is the schema of the settings of a component endpoint.
+func apiEndpointSchema(component string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: fmt.Sprintf("Settings of the %s endpoint. The unset settings fall back to the top-level ones.", component),
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"uri": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: fmt.Sprintf("mimir %s base url. Defaults to `%s_uri`, then to `uri`.", component, component),
+				},
+				"token": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "When set, will use this token for Bearer auth to the API.",
+				},
+				"username": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "When set, will use this username for BASIC auth to the API.",
+				},
+				"password": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "When set, will use this password for BASIC auth to the API.",
+				},
+				"insecure": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: "When using https, this disables TLS verification of the host. Defaults to the top-level `insecure`, which `false` overrides.",
+				},
+				"cert": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Client cert for client authentication",
+				},
+				"key": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Client key for client authentication",
+				},
+				"ca": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Client ca for client authentication",
+				},
+				"headers": {
+					Type:        schema.TypeMap,
+					Elem:        &schema.Schema{Type: schema.TypeString},
+					Optional:    true,
+					Description: "A map of header names and values to set on the requests, merged with the top-level headers.",
+				},
+				"timeout": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Description:  "When set, will cause requests taking longer than this time (in seconds) to be aborted.",
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+			},
+		},
+	}
+}
+
+func expandAPIEndpointOpt(d *schema.ResourceData, key string) *apiEndpointOpt {
+	v := d.Get(key).([]interface{})
+	if len(v) == 0 || v[0] == nil {
+		return nil
+	}
+	data := v[0].(map[string]interface{})
+
+	opt := &apiEndpointOpt{
+		uri:      data["uri"].(string),
+		token:    data["token"].(string),
+		username: data["username"].(string),
+		password: data["password"].(string),
+		cert:     data["cert"].(string),
+		key:      data["key"].(string),
+		ca:       data["ca"].(string),
+		headers:  expandStringMap(data["headers"].(map[string]interface{})),
+		timeout:  data["timeout"].(int),
+	}
+	// An explicit false overrides the top-level insecure setting. The raw
+	// config is not available when configuring the provider.
+	if insecure, ok := d.GetOkExists(fmt.Sprintf("%s.0.insecure", key)); ok { //nolint:staticcheck
+		value := insecure.(bool)
+		opt.insecure = &value
+	}
+
+	return opt
+}
+
+func expandAPIOAuth2Opt(v []interface{}) *apiOAuth2Opt {
+	if len(v) == 0 || v[0] == nil {
+		return nil
+	}
+	data := v[0].(map[string]interface{})
+
+	return &apiOAuth2Opt{
+		token_url:       data["token_url"].(string),
+		client_id:       data["client_id"].(string),
+		client_secret:   data["client_secret"].(string),
+		scopes:          expandStringArray(data["scopes"].([]interface{})),
+		endpoint_params: expandStringMap(data["endpoint_params"].(map[string]interface{})),
+		insecure:        data["insecure"].(bool),
+		cert:            data["cert"].(string),
+		key:             data["key"].(string),
+		ca:              data["ca"].(string),
+	}
+}
@@ -2,11 +2,20 @@ package mimir
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/common/model"
 	"gopkg.in/yaml.v3"
 )
 
@@ -19,37 +28,48 @@ func resourcemimirAlertmanagerConfig() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
-		Schema: resourceMimirAlertmanagerConfigSchemaV1(),
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		CustomizeDiff: resourcemimirAlertmanagerConfigCustomizeDiff,
+		Schema:        resourceMimirAlertmanagerConfigSchemaV1(),
 	}
 }
 
 func resourcemimirAlertmanagerConfigCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*api_client)
 	path := "/api/v1/alerts"
-	resp, err := alertmanagerConfigCreateUpdate(client, d, path)
+	resp, err := alertmanagerConfigCreateUpdate(ctx, client, d, path)
 	baseMsg := "Cannot create alertmanager config"
 	fullurl := fmt.Sprintf("%s%s", client.uri, path)
 	err = handleHTTPError(err, resp, fullurl, baseMsg)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	d.SetId(client.headers["X-Scope-OrgID"])
+	d.Set("secrets_hash", hashSecrets(expandAlertmanagerConfig(d)))
+	if err := alertmanagerConfigVerify(ctx, client, d); err != nil {
+		return diagFromErr(err)
+	}
 	return resourcemimirAlertmanagerConfigRead(ctx, d, meta)
 }
 
 func resourcemimirAlertmanagerConfigRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*api_client)
 	path := "/api/v1/alerts"
-	resp, err := client.send_request("alertmanager", "GET", path, "", make(map[string]string))
+	resp, err := client.send_request(ctx, "alertmanager", "GET", path, "", make(map[string]string))
 	baseMsg := "Cannot read alertmanager config"
 	fullurl := fmt.Sprintf("%s%s", client.uri, path)
 	err = handleHTTPError(err, resp, fullurl, baseMsg)
 	if err != nil {
-		if strings.Contains(err.Error(), "response code '404'") {
+		if isNotFoundError(err) {
 			d.SetId("")
 			return nil
 		}
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	var alertmanagerUserConf alertmanagerUserConfig
@@ -58,39 +78,84 @@ func resourcemimirAlertmanagerConfigRead(ctx context.Context, d *schema.Resource
 	var alertmanagerConf alertmanagerConfig
 	yaml.Unmarshal([]byte(alertmanagerUserConf.AlertmanagerConfig), &alertmanagerConf)
 
+	// Depending on the version and configuration, secrets may be returned
+	// masked: keep the ones known from the state instead.
+	if hasMaskedSecrets(&alertmanagerConf) {
+		restoreMaskedSecrets(&alertmanagerConf, expandAlertmanagerConfig(d))
+	} else {
+		d.Set("secrets_hash", hashSecrets(&alertmanagerConf))
+	}
+
 	if alertmanagerConf.Global != nil {
 		d.Set("global", flattenGlobalConfig(alertmanagerConf.Global))
 	}
-	d.Set("time_interval", flattenMuteTimeIntervalConfig(alertmanagerConf.MuteTimeIntervals))
+	timeIntervalsKey, err := alertmanagerTimeIntervalsKey(&alertmanagerConf)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if timeIntervalsKey == "" {
+		timeIntervalsKey = d.Get("time_intervals_key").(string)
+	}
+	d.Set("time_interval", flattenMuteTimeIntervalConfig(append(alertmanagerConf.MuteTimeIntervals, alertmanagerConf.TimeIntervals...)))
+	d.Set("time_intervals_key", timeIntervalsKey)
 	d.Set("inhibit_rule", flattenInhibitRuleConfig(alertmanagerConf.InhibitRules))
-	d.Set("receiver", flattenReceiverConfig(alertmanagerConf.Receivers))
+	receivers, err := flattenReceiverConfig(alertmanagerConf.Receivers)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("receiver", receivers)
 	d.Set("route", flattenRouteConfig(alertmanagerConf.Route))
 	d.Set("templates", alertmanagerConf.Templates)
 	d.Set("templates_files", alertmanagerUserConf.TemplateFiles)
+	d.Set("trust_server_secrets", d.Get("trust_server_secrets"))
+	d.Set("verify", d.Get("verify"))
+	d.Set("verify_timeout", d.Get("verify_timeout"))
 
 	return diag.Diagnostics{}
 }
 
 func resourcemimirAlertmanagerConfigUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*api_client)
-	path := "/api/v1/alerts"
-	resp, err := alertmanagerConfigCreateUpdate(client, d, path)
-	baseMsg := "Cannot update alertmanager config"
-	fullurl := fmt.Sprintf("%s%s", client.uri, path)
-	err = handleHTTPError(err, resp, fullurl, baseMsg)
-	if err != nil {
-		return diag.FromErr(err)
+	// A changed secret may only show in the hash, as the masked secrets of
+	// the state hide it, so the config is sent whenever the hash changes.
+	// Only the first hash recorded after an import, of the secrets trusted
+	// on the server, and the verification settings are just recorded.
+	oldHash, _ := d.GetChange("secrets_hash")
+	secretsChanged := d.HasChange("secrets_hash") && oldHash.(string) != ""
+	if secretsChanged || d.HasChangesExcept("secrets_hash", "trust_server_secrets", "verify", "verify_timeout") {
+		client := meta.(*api_client)
+		path := "/api/v1/alerts"
+		resp, err := alertmanagerConfigCreateUpdate(ctx, client, d, path)
+		baseMsg := "Cannot update alertmanager config"
+		fullurl := fmt.Sprintf("%s%s", client.uri, path)
+		err = handleHTTPError(err, resp, fullurl, baseMsg)
+		if err != nil {
+			return diagFromErr(err)
+		}
+		if err := alertmanagerConfigVerify(ctx, client, d); err != nil {
+			return diagFromErr(err)
+		}
 	}
+	d.Set("secrets_hash", hashSecrets(expandAlertmanagerConfig(d)))
 	return resourcemimirAlertmanagerConfigRead(ctx, d, meta)
 }
 
+func resourcemimirAlertmanagerConfigCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	// Secrets returned masked by the API are restored from the state on
+	// read, so compare the hash of the configured secrets to detect real
+	// changes, even when the masked values are trusted.
+	if d.Get("secrets_hash").(string) != hashSecrets(expandAlertmanagerConfig(d)) {
+		return d.SetNewComputed("secrets_hash")
+	}
+	return nil
+}
+
 func resourcemimirAlertmanagerConfigDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*api_client)
 	path := "/api/v1/alerts"
-	_, err := client.send_request("alertmanager", "DELETE", path, "", make(map[string]string))
+	_, err := client.send_request(ctx, "alertmanager", "DELETE", path, "", make(map[string]string))
 	if err != nil {
-		return diag.FromErr(fmt.Errorf(
-			"Cannot delete alertmanager config from %s: %v",
+		return diagFromErr(fmt.Errorf(
+			"Cannot delete alertmanager config from %s: %w",
 			fmt.Sprintf("%s%s", client.uri, path),
 			err))
 	}
@@ -99,27 +164,262 @@ func resourcemimirAlertmanagerConfigDelete(ctx context.Context, d *schema.Resour
 	return diag.Diagnostics{}
 }
 
-func alertmanagerConfigCreateUpdate(client *api_client, d *schema.ResourceData, path string) (string, error) {
-	headers := map[string]string{"Content-Type": "application/yaml"}
+// resourceDataGetter is implemented by both schema.ResourceData and
+// schema.ResourceDiff.
+type resourceDataGetter interface {
+	Get(key string) interface{}
+}
 
+func expandAlertmanagerConfig(d resourceDataGetter) *alertmanagerConfig {
 	alertmanagerConf := &alertmanagerConfig{
-		Global:            expandGlobalConfig(d.Get("global").([]interface{})),
-		MuteTimeIntervals: expandMuteTimeIntervalConfig(d.Get("time_interval").([]interface{})),
-		InhibitRules:      expandInhibitRuleConfig(d.Get("inhibit_rule").([]interface{})),
-		Receivers:         expandReceiverConfig(d.Get("receiver").([]interface{})),
-		Route:             expandRouteConfig(d.Get("route").([]interface{})),
-		Templates:         expandStringArray(d.Get("templates").([]interface{})),
+		Global:       expandGlobalConfig(d.Get("global").([]interface{})),
+		InhibitRules: expandInhibitRuleConfig(d.Get("inhibit_rule").([]interface{})),
+		Receivers:    expandReceiverConfig(d.Get("receiver").([]interface{})),
+		Route:        expandRouteConfig(d.Get("route").([]interface{})),
+		Templates:    expandStringArray(d.Get("templates").([]interface{})),
+	}
+
+	timeIntervals := expandMuteTimeIntervalConfig(d.Get("time_interval").([]interface{}))
+	if d.Get("time_intervals_key").(string) == "time_intervals" {
+		alertmanagerConf.TimeIntervals = timeIntervals
+	} else {
+		alertmanagerConf.MuteTimeIntervals = timeIntervals
+	}
+	return alertmanagerConf
+}
+
+// alertmanagerTimeIntervalsKey returns the top-level key the time intervals
+// of a config are defined under, or an empty string if there are none. As
+// the time_interval blocks are written to a single key, a config which uses
+// both keys cannot be managed without a permanent diff.
+func alertmanagerTimeIntervalsKey(conf *alertmanagerConfig) (string, error) {
+	switch {
+	case len(conf.MuteTimeIntervals) > 0 && len(conf.TimeIntervals) > 0:
+		return "", fmt.Errorf("The alertmanager config defines time intervals under both mute_time_intervals and time_intervals: move them under a single key")
+	case len(conf.MuteTimeIntervals) > 0:
+		return "mute_time_intervals", nil
+	case len(conf.TimeIntervals) > 0:
+		return "time_intervals", nil
 	}
+	return "", nil
+}
+
+func alertmanagerConfigCreateUpdate(ctx context.Context, client *api_client, d *schema.ResourceData, path string) (string, error) {
+	headers := map[string]string{"Content-Type": "application/yaml"}
+
+	alertmanagerUserConf := expandAlertmanagerUserConfig(d)
+	dataBytes, _ := yaml.Marshal(&alertmanagerUserConf)
+
+	resp, err := client.send_request(ctx, "alertmanager", "POST", path, string(dataBytes), headers)
+
+	return resp, err
+}
+
+// expandAlertmanagerUserConfig builds the document accepted by the Mimir
+// alertmanager API, the alertmanager config with its template files.
+func expandAlertmanagerUserConfig(d resourceDataGetter) *alertmanagerUserConfig {
+	alertmanagerConf := expandAlertmanagerConfig(d)
 	alertmanagerConfBytes, _ := yaml.Marshal(&alertmanagerConf)
 
-	alertmanagerUserConf := &alertmanagerUserConfig{
+	return &alertmanagerUserConfig{
 		TemplateFiles:      expandStringMap(d.Get("templates_files").(map[string]interface{})),
 		AlertmanagerConfig: string(alertmanagerConfBytes),
 	}
+}
 
-	dataBytes, _ := yaml.Marshal(&alertmanagerUserConf)
+// alertmanagerStatus is the part of the alertmanager status API response
+// holding the running config.
+type alertmanagerStatus struct {
+	Config struct {
+		Original string `json:"original"`
+	} `json:"config"`
+}
 
-	resp, err := client.send_request("alertmanager", "POST", path, string(dataBytes), headers)
+// alertmanagerConfigVerify polls the alertmanager status until the running
+// config matches the configured one, as the alertmanager applies a new
+// config asynchronously and keeps the previous one if it fails to.
+func alertmanagerConfigVerify(ctx context.Context, client *api_client, d *schema.ResourceData) error {
+	if !d.Get("verify").(bool) {
+		return nil
+	}
 
-	return resp, err
+	timeout, err := model.ParseDuration(d.Get("verify_timeout").(string))
+	if err != nil {
+		return err
+	}
+
+	expected, err := yaml.Marshal(expandAlertmanagerConfig(d))
+	if err != nil {
+		return err
+	}
+
+	path := "/alertmanager/api/v2/status"
+	fullurl := fmt.Sprintf("%s%s", client.endpoints["alertmanager"].uri, path)
+	err = resource.RetryContext(ctx, time.Duration(timeout), func() *resource.RetryError {
+		resp, err := client.send_request(ctx, "alertmanager", "GET", path, "", make(map[string]string))
+		if err != nil {
+			// The alertmanager may be restarting or not have loaded the
+			// tenant yet, but the other client errors are not transient.
+			var apiErr *apiError
+			if errors.As(err, &apiErr) && apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 &&
+				apiErr.StatusCode != http.StatusNotFound && apiErr.StatusCode != http.StatusTooManyRequests {
+				return resource.NonRetryableError(err)
+			}
+			return resource.RetryableError(err)
+		}
+
+		var status alertmanagerStatus
+		if err := json.Unmarshal([]byte(resp), &status); err != nil {
+			return resource.NonRetryableError(fmt.Errorf("Cannot decode alertmanager status: %v", err))
+		}
+
+		if diff := alertmanagerConfigRunningDiff(string(expected), status.Config.Original); diff != "" {
+			return resource.RetryableError(fmt.Errorf("the running config does not match the configured one:\n%s", diff))
+		}
+		return nil
+	})
+	if timeoutErr, ok := err.(*resource.TimeoutError); ok && timeoutErr.LastError != nil {
+		return fmt.Errorf("Cannot verify alertmanager config from %s: not running after %s, %v", fullurl, timeout, timeoutErr.LastError)
+	}
+	if err != nil {
+		return fmt.Errorf("Cannot verify alertmanager config from %s: %w", fullurl, err)
+	}
+	return nil
+}
+
+// alertmanagerConfigDiff returns the top-level sections of two normalized
+// configs which differ, with their first differing line.
+func alertmanagerConfigDiff(want, got string) string {
+	var wantSections, gotSections map[string]interface{}
+	if err := yaml.Unmarshal([]byte(want), &wantSections); err != nil {
+		return err.Error()
+	}
+	if err := yaml.Unmarshal([]byte(got), &gotSections); err != nil {
+		return err.Error()
+	}
+
+	keys := make(map[string]bool)
+	for k := range wantSections {
+		keys[k] = true
+	}
+	for k := range gotSections {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var diff []string
+	for _, k := range sorted {
+		if reflect.DeepEqual(wantSections[k], gotSections[k]) {
+			continue
+		}
+		wantLines := alertmanagerConfigSectionLines(wantSections[k])
+		gotLines := alertmanagerConfigSectionLines(gotSections[k])
+		i := 0
+		for i < len(wantLines) && i < len(gotLines) && wantLines[i] == gotLines[i] {
+			i++
+		}
+		wantLine, gotLine := "<none>", "<none>"
+		if i < len(wantLines) {
+			wantLine = strings.TrimSpace(wantLines[i])
+		}
+		if i < len(gotLines) {
+			gotLine = strings.TrimSpace(gotLines[i])
+		}
+		diff = append(diff, fmt.Sprintf("  %s: configured %q, running %q", k, wantLine, gotLine))
+	}
+
+	return strings.Join(diff, "\n")
+}
+
+func alertmanagerConfigSectionLines(v interface{}) []string {
+	if v == nil {
+		return nil
+	}
+	out, _ := yaml.Marshal(v)
+	return strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+}
+
+// alertmanagerConfigRunningDiff compares the configured config with the
+// running one, as reported by the alertmanager status, and returns their
+// differences. The running config is reported as loaded by the alertmanager,
+// with the defaults filled in and the secrets masked, so the configured one is
+// loaded the same way. When the alertmanager library cannot load the configs,
+// e.g. using integrations newer than it, the configured settings are looked up
+// in the running config instead, the masked secrets matching any value.
+func alertmanagerConfigRunningDiff(expected, running string) string {
+	want, err := config.Load(expected)
+	if err == nil {
+		var got *config.Config
+		if got, err = config.Load(running); err == nil {
+			if want.String() == got.String() {
+				return ""
+			}
+			return alertmanagerConfigDiff(want.String(), got.String())
+		}
+	}
+
+	var wantValue, gotValue interface{}
+	if err := yaml.Unmarshal([]byte(expected), &wantValue); err != nil {
+		return err.Error()
+	}
+	if err := yaml.Unmarshal([]byte(running), &gotValue); err != nil {
+		return err.Error()
+	}
+	if path := alertmanagerConfigMissing(wantValue, gotValue, ""); path != "" {
+		return fmt.Sprintf("  %s: configured value not running", path)
+	}
+	return ""
+}
+
+// alertmanagerConfigMissing returns the path of the first value of want which
+// is not in got, or an empty string if got contains all of want. The values
+// themselves are left out, as they may be secrets.
+func alertmanagerConfigMissing(want, got interface{}, path string) string {
+	switch w := want.(type) {
+	case nil:
+		return ""
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			return path
+		}
+		keys := make([]string, 0, len(w))
+		for k := range w {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			if missing := alertmanagerConfigMissing(w[k], g[k], p); missing != "" {
+				return missing
+			}
+		}
+		return ""
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok || len(g) != len(w) {
+			return path
+		}
+		for i := range w {
+			if missing := alertmanagerConfigMissing(w[i], g[i], fmt.Sprintf("%s[%d]", path, i)); missing != "" {
+				return missing
+			}
+		}
+		return ""
+	default:
+		if g, ok := got.(string); ok && isMaskedSecret(g) {
+			return ""
+		}
+		if fmt.Sprint(want) == fmt.Sprint(got) {
+			return ""
+		}
+		return path
+	}
 }
@@ -1,9 +1,14 @@
 package mimir
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gopkg.in/yaml.v3"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 )
@@ -24,7 +29,7 @@ func testAccCheckMimirAlertmanagerConfigExists(n string, client *api_client) res
 		}
 
 		/* Make a throw-away API object to read from the API */
-		_, err := client.send_request("alertmanager", "GET", "/api/v1/alerts", "", make(map[string]string))
+		_, err := client.send_request(context.Background(), "alertmanager", "GET", "/api/v1/alerts", "", make(map[string]string))
 		if err != nil {
 			return err
 		}
@@ -43,7 +48,7 @@ func testAccCheckMimirAlertmanagerConfigDestroy(s *terraform.State) error {
 		if rs.Type != "mimir_alertmanager_config" {
 			continue
 		}
-		_, err := client.send_request("alertmanager", "GET", "/api/v1/alerts", "", make(map[string]string))
+		_, err := client.send_request(context.Background(), "alertmanager", "GET", "/api/v1/alerts", "", make(map[string]string))
 		// If the error is equivalent to 404 not found, the widget is destroyed.
 		// Otherwise return the error
 		if !strings.Contains(err.Error(), "not found") {
@@ -682,3 +687,928 @@ const testAccResourceAlertmanagerConfig_EmailReceiver_update = `
       }
     }
 `
+
+func TestAccResourceAlertmanagerConfig_Secrets(t *testing.T) {
+	// Init client
+	client, err := NewAPIClient(setupClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMimirAlertmanagerConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceAlertmanagerConfig_Secrets,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirAlertmanagerConfigExists("mimir_alertmanager_config.mytenant", client),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "trust_server_secrets", "true"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.webhook_configs.0.url", "http://secret.example.com/hook"),
+					resource.TestCheckResourceAttrSet("mimir_alertmanager_config.mytenant", "secrets_hash"),
+				),
+			},
+			{
+				Config: testAccResourceAlertmanagerConfig_Secrets_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirAlertmanagerConfigExists("mimir_alertmanager_config.mytenant", client),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.webhook_configs.0.url", "http://secret2.example.com/hook"),
+					resource.TestCheckResourceAttrSet("mimir_alertmanager_config.mytenant", "secrets_hash"),
+				),
+			},
+		},
+	})
+}
+
+const testAccResourceAlertmanagerConfig_Secrets = `
+    resource "mimir_alertmanager_config" "mytenant" {
+      trust_server_secrets = true
+      route {
+        group_by = ["..."]
+        group_wait = "30s"
+        group_interval = "5m"
+        repeat_interval = "1h"
+        receiver = "webhook"
+      }
+      receiver {
+        name = "webhook"
+        webhook_configs {
+          url = "http://secret.example.com/hook"
+        }
+      }
+    }
+`
+
+const testAccResourceAlertmanagerConfig_Secrets_update = `
+    resource "mimir_alertmanager_config" "mytenant" {
+      trust_server_secrets = true
+      route {
+        group_by = ["..."]
+        group_wait = "30s"
+        group_interval = "5m"
+        repeat_interval = "1h"
+        receiver = "webhook"
+      }
+      receiver {
+        name = "webhook"
+        webhook_configs {
+          url = "http://secret2.example.com/hook"
+        }
+      }
+    }
+`
+
+func TestAccResourceAlertmanagerConfig_Verify(t *testing.T) {
+	// Init client
+	client, err := NewAPIClient(setupClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMimirAlertmanagerConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceAlertmanagerConfig_Verify,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirAlertmanagerConfigExists("mimir_alertmanager_config.mytenant", client),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "verify", "true"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "verify_timeout", "2m"),
+				),
+			},
+		},
+	})
+}
+
+const testAccResourceAlertmanagerConfig_Verify = `
+    resource "mimir_alertmanager_config" "mytenant" {
+      verify         = true
+      verify_timeout = "2m"
+      route {
+        group_by = ["..."]
+        group_wait = "30s"
+        group_interval = "5m"
+        repeat_interval = "1h"
+        receiver = "pagerduty"
+      }
+      receiver {
+        name = "pagerduty"
+        pagerduty_configs {
+          routing_key = "secret"
+        }
+      }
+    }
+`
+
+func TestAccResourceAlertmanagerConfig_DiscordReceiver(t *testing.T) {
+	// Init client
+	client, err := NewAPIClient(setupClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMimirAlertmanagerConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceAlertmanagerConfig_DiscordReceiver,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirAlertmanagerConfigExists("mimir_alertmanager_config.mytenant", client),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "route.0.receiver", "discord"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.name", "discord"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.discord_configs.0.webhook_url", "https://discord.com/api/webhooks/123/secret"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.discord_configs.0.title", "{{ .CommonLabels.alertname }}"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.discord_configs.0.send_resolved", "true"),
+				),
+			},
+			{
+				Config: testAccResourceAlertmanagerConfig_DiscordReceiver_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirAlertmanagerConfigExists("mimir_alertmanager_config.mytenant", client),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "route.0.receiver", "discord"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.name", "discord"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.discord_configs.0.webhook_url", "https://discord.com/api/webhooks/123/secret"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.discord_configs.0.message", "{{ .CommonAnnotations.summary }}"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.discord_configs.0.send_resolved", "false"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.discord_configs.0.http_config.0.proxy_url", "http://proxy.example.com:3128"),
+				),
+			},
+		},
+	})
+}
+
+const testAccResourceAlertmanagerConfig_DiscordReceiver = `
+    resource "mimir_alertmanager_config" "mytenant" {
+      route {
+        group_by = ["..."]
+        group_wait = "30s"
+        group_interval = "5m"
+        repeat_interval = "1h"
+        receiver = "discord"
+      }
+      receiver {
+        name = "discord"
+        discord_configs {
+          webhook_url = "https://discord.com/api/webhooks/123/secret"
+          title = "{{ .CommonLabels.alertname }}"
+        }
+      }
+    }
+`
+
+const testAccResourceAlertmanagerConfig_DiscordReceiver_update = `
+    resource "mimir_alertmanager_config" "mytenant" {
+      route {
+        group_by = ["..."]
+        group_wait = "30s"
+        group_interval = "5m"
+        repeat_interval = "1h"
+        receiver = "discord"
+      }
+      receiver {
+        name = "discord"
+        discord_configs {
+          webhook_url = "https://discord.com/api/webhooks/123/secret"
+          message = "{{ .CommonAnnotations.summary }}"
+          send_resolved = false
+          http_config {
+            proxy_url = "http://proxy.example.com:3128"
+          }
+        }
+      }
+    }
+`
+
+func TestAccResourceAlertmanagerConfig_MSTeamsReceiver(t *testing.T) {
+	// Init client
+	client, err := NewAPIClient(setupClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMimirAlertmanagerConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceAlertmanagerConfig_MSTeamsReceiver,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirAlertmanagerConfigExists("mimir_alertmanager_config.mytenant", client),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "route.0.receiver", "msteams"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.name", "msteams"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.msteams_configs.0.webhook_url", "https://example.webhook.office.com/webhookb2/secret"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.msteams_configs.0.title", "{{ .CommonLabels.alertname }}"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.msteams_configs.0.text", "{{ .CommonAnnotations.summary }}"),
+				),
+			},
+			{
+				Config: testAccResourceAlertmanagerConfig_MSTeamsReceiver_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirAlertmanagerConfigExists("mimir_alertmanager_config.mytenant", client),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "route.0.receiver", "msteams"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.name", "msteams"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.msteams_configs.0.webhook_url", "https://example.webhook.office.com/webhookb2/secret"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.msteams_configs.0.summary", "{{ .CommonLabels.alertname }} is {{ .Status }}"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.msteams_configs.0.send_resolved", "false"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.msteams_configs.0.http_config.0.follow_redirects", "false"),
+				),
+			},
+		},
+	})
+}
+
+const testAccResourceAlertmanagerConfig_MSTeamsReceiver = `
+    resource "mimir_alertmanager_config" "mytenant" {
+      route {
+        group_by = ["..."]
+        group_wait = "30s"
+        group_interval = "5m"
+        repeat_interval = "1h"
+        receiver = "msteams"
+      }
+      receiver {
+        name = "msteams"
+        msteams_configs {
+          webhook_url = "https://example.webhook.office.com/webhookb2/secret"
+          title = "{{ .CommonLabels.alertname }}"
+          text = "{{ .CommonAnnotations.summary }}"
+        }
+      }
+    }
+`
+
+const testAccResourceAlertmanagerConfig_MSTeamsReceiver_update = `
+    resource "mimir_alertmanager_config" "mytenant" {
+      route {
+        group_by = ["..."]
+        group_wait = "30s"
+        group_interval = "5m"
+        repeat_interval = "1h"
+        receiver = "msteams"
+      }
+      receiver {
+        name = "msteams"
+        msteams_configs {
+          webhook_url = "https://example.webhook.office.com/webhookb2/secret"
+          summary = "{{ .CommonLabels.alertname }} is {{ .Status }}"
+          send_resolved = false
+          http_config {
+            follow_redirects = false
+          }
+        }
+      }
+    }
+`
+
+func TestAccResourceAlertmanagerConfig_WebexReceiver(t *testing.T) {
+	// Init client
+	client, err := NewAPIClient(setupClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMimirAlertmanagerConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceAlertmanagerConfig_WebexReceiver,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirAlertmanagerConfigExists("mimir_alertmanager_config.mytenant", client),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "route.0.receiver", "webex"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.name", "webex"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.webex_configs.0.room_id", "room1"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.webex_configs.0.message", "{{ .CommonAnnotations.summary }}"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.webex_configs.0.http_config.0.authorization.0.credentials", "secret"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "global.0.webex_api_url", "https://webexapis.com/v1/messages"),
+				),
+			},
+			{
+				Config: testAccResourceAlertmanagerConfig_WebexReceiver_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirAlertmanagerConfigExists("mimir_alertmanager_config.mytenant", client),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "route.0.receiver", "webex"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.name", "webex"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.webex_configs.0.room_id", "room2"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.webex_configs.0.api_url", "https://webex.example.com/v1/messages"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.webex_configs.0.http_config.0.authorization.0.credentials", "secret2"),
+				),
+			},
+		},
+	})
+}
+
+const testAccResourceAlertmanagerConfig_WebexReceiver = `
+    resource "mimir_alertmanager_config" "mytenant" {
+      global {
+        webex_api_url = "https://webexapis.com/v1/messages"
+      }
+      route {
+        group_by = ["..."]
+        group_wait = "30s"
+        group_interval = "5m"
+        repeat_interval = "1h"
+        receiver = "webex"
+      }
+      receiver {
+        name = "webex"
+        webex_configs {
+          room_id = "room1"
+          message = "{{ .CommonAnnotations.summary }}"
+          http_config {
+            authorization {
+              type = "Bearer"
+              credentials = "secret"
+            }
+          }
+        }
+      }
+    }
+`
+
+const testAccResourceAlertmanagerConfig_WebexReceiver_update = `
+    resource "mimir_alertmanager_config" "mytenant" {
+      route {
+        group_by = ["..."]
+        group_wait = "30s"
+        group_interval = "5m"
+        repeat_interval = "1h"
+        receiver = "webex"
+      }
+      receiver {
+        name = "webex"
+        webex_configs {
+          room_id = "room2"
+          api_url = "https://webex.example.com/v1/messages"
+          http_config {
+            authorization {
+              type = "Bearer"
+              credentials = "secret2"
+            }
+          }
+        }
+      }
+    }
+`
+
+func TestAccResourceAlertmanagerConfig_RocketchatReceiver(t *testing.T) {
+	// Init client
+	client, err := NewAPIClient(setupClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMimirAlertmanagerConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceAlertmanagerConfig_RocketchatReceiver,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirAlertmanagerConfigExists("mimir_alertmanager_config.mytenant", client),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "route.0.receiver", "rocketchat"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.name", "rocketchat"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.rocketchat_configs.0.token", "secret"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.rocketchat_configs.0.token_id", "user1"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.rocketchat_configs.0.channel", "#alerts"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.rocketchat_configs.0.fields.0.title", "Severity"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.rocketchat_configs.0.fields.0.short", "true"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.rocketchat_configs.0.actions.0.text", "Runbook"),
+				),
+			},
+			{
+				Config: testAccResourceAlertmanagerConfig_RocketchatReceiver_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirAlertmanagerConfigExists("mimir_alertmanager_config.mytenant", client),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "route.0.receiver", "rocketchat"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.name", "rocketchat"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.rocketchat_configs.0.channel", "#alerts2"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "global.0.rocketchat_api_url", "https://rocketchat.example.com"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "global.0.rocketchat_token_id", "user2"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.rocketchat_configs.0.actions.0.url", "https://runbooks.example.com/2"),
+				),
+			},
+		},
+	})
+}
+
+const testAccResourceAlertmanagerConfig_RocketchatReceiver = `
+    resource "mimir_alertmanager_config" "mytenant" {
+      route {
+        group_by = ["..."]
+        group_wait = "30s"
+        group_interval = "5m"
+        repeat_interval = "1h"
+        receiver = "rocketchat"
+      }
+      receiver {
+        name = "rocketchat"
+        rocketchat_configs {
+          token = "secret"
+          token_id = "user1"
+          channel = "#alerts"
+          fields {
+            title = "Severity"
+            value = "{{ .CommonLabels.severity }}"
+            short = true
+          }
+          actions {
+            type = "button"
+            text = "Runbook"
+            url = "https://runbooks.example.com/1"
+          }
+        }
+      }
+    }
+`
+
+const testAccResourceAlertmanagerConfig_RocketchatReceiver_update = `
+    resource "mimir_alertmanager_config" "mytenant" {
+      global {
+        rocketchat_api_url = "https://rocketchat.example.com"
+        rocketchat_token = "secret2"
+        rocketchat_token_id = "user2"
+      }
+      route {
+        group_by = ["..."]
+        group_wait = "30s"
+        group_interval = "5m"
+        repeat_interval = "1h"
+        receiver = "rocketchat"
+      }
+      receiver {
+        name = "rocketchat"
+        rocketchat_configs {
+          channel = "#alerts2"
+          actions {
+            type = "button"
+            text = "Runbook"
+            url = "https://runbooks.example.com/2"
+          }
+        }
+      }
+    }
+`
+
+func TestAccResourceAlertmanagerConfig_JiraReceiver(t *testing.T) {
+	// Init client
+	client, err := NewAPIClient(setupClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMimirAlertmanagerConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceAlertmanagerConfig_JiraReceiver,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirAlertmanagerConfigExists("mimir_alertmanager_config.mytenant", client),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "route.0.receiver", "jira"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.name", "jira"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.jira_configs.0.project", "OPS"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.jira_configs.0.issue_type", "Bug"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.jira_configs.0.summary", "{{ .CommonLabels.alertname }}"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.jira_configs.0.labels.0", "alertmanager"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.jira_configs.0.fields.customfield_10001", "{\"value\":\"production\"}"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "global.0.jira_api_url", "https://jira.example.com/rest/api/2"),
+				),
+			},
+			{
+				Config: testAccResourceAlertmanagerConfig_JiraReceiver_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirAlertmanagerConfigExists("mimir_alertmanager_config.mytenant", client),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "route.0.receiver", "jira"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.name", "jira"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.jira_configs.0.project", "OPS"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.jira_configs.0.priority", "High"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.jira_configs.0.reopen_transition", "Reopen"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.jira_configs.0.resolve_transition", "Done"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.jira_configs.0.reopen_duration", "1h"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.jira_configs.0.http_config.0.basic_auth.0.username", "alertmanager"),
+				),
+			},
+		},
+	})
+}
+
+const testAccResourceAlertmanagerConfig_JiraReceiver = `
+    resource "mimir_alertmanager_config" "mytenant" {
+      global {
+        jira_api_url = "https://jira.example.com/rest/api/2"
+      }
+      route {
+        group_by = ["..."]
+        group_wait = "30s"
+        group_interval = "5m"
+        repeat_interval = "1h"
+        receiver = "jira"
+      }
+      receiver {
+        name = "jira"
+        jira_configs {
+          project = "OPS"
+          issue_type = "Bug"
+          summary = "{{ .CommonLabels.alertname }}"
+          description = "{{ .CommonAnnotations.description }}"
+          labels = ["alertmanager"]
+          fields = {
+            customfield_10001 = jsonencode({ value = "production" })
+          }
+        }
+      }
+    }
+`
+
+const testAccResourceAlertmanagerConfig_JiraReceiver_update = `
+    resource "mimir_alertmanager_config" "mytenant" {
+      global {
+        jira_api_url = "https://jira.example.com/rest/api/2"
+      }
+      route {
+        group_by = ["..."]
+        group_wait = "30s"
+        group_interval = "5m"
+        repeat_interval = "1h"
+        receiver = "jira"
+      }
+      receiver {
+        name = "jira"
+        jira_configs {
+          project = "OPS"
+          issue_type = "Bug"
+          summary = "{{ .CommonLabels.alertname }}"
+          priority = "High"
+          reopen_transition = "Reopen"
+          resolve_transition = "Done"
+          reopen_duration = "1h"
+          http_config {
+            basic_auth {
+              username = "alertmanager"
+              password = "secret"
+            }
+          }
+        }
+      }
+    }
+`
+
+func TestAccResourceAlertmanagerConfig_HTTPConfig(t *testing.T) {
+	// Init client
+	client, err := NewAPIClient(setupClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMimirAlertmanagerConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceAlertmanagerConfig_HTTPConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirAlertmanagerConfigExists("mimir_alertmanager_config.mytenant", client),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "route.0.receiver", "webhook"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.name", "webhook"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.webhook_configs.0.http_config.0.proxy_url", "http://proxy.example.com:3128"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.webhook_configs.0.http_config.0.no_proxy", "localhost,.internal"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.webhook_configs.0.http_config.0.proxy_connect_header.X-Proxy-Auth", "secret"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.webhook_configs.0.http_config.0.enable_http2", "false"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.webhook_configs.0.http_config.0.tls_config.0.min_version", "TLS12"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "global.0.http_config.0.proxy_from_environment", "true"),
+				),
+			},
+			{
+				Config: testAccResourceAlertmanagerConfig_HTTPConfig_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirAlertmanagerConfigExists("mimir_alertmanager_config.mytenant", client),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "route.0.receiver", "webhook"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.name", "webhook"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.webhook_configs.0.http_config.0.oauth2.0.proxy_url", "http://proxy.example.com:3128"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.webhook_configs.0.http_config.0.oauth2.0.tls_config.0.server_name", "auth.example.com"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.webhook_configs.0.http_config.0.tls_config.0.max_version", "TLS13"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "receiver.0.webhook_configs.0.http_config.0.enable_http2", "true"),
+				),
+			},
+		},
+	})
+}
+
+const testAccResourceAlertmanagerConfig_HTTPConfig = `
+    resource "mimir_alertmanager_config" "mytenant" {
+      global {
+        http_config {
+          proxy_from_environment = true
+        }
+      }
+      route {
+        group_by = ["..."]
+        group_wait = "30s"
+        group_interval = "5m"
+        repeat_interval = "1h"
+        receiver = "webhook"
+      }
+      receiver {
+        name = "webhook"
+        webhook_configs {
+          url = "http://webhook.example.com/hook"
+          http_config {
+            proxy_url = "http://proxy.example.com:3128"
+            no_proxy = "localhost,.internal"
+            proxy_connect_header = {
+              X-Proxy-Auth = "secret"
+            }
+            enable_http2 = false
+            tls_config {
+              min_version = "TLS12"
+            }
+          }
+        }
+      }
+    }
+`
+
+const testAccResourceAlertmanagerConfig_HTTPConfig_update = `
+    resource "mimir_alertmanager_config" "mytenant" {
+      route {
+        group_by = ["..."]
+        group_wait = "30s"
+        group_interval = "5m"
+        repeat_interval = "1h"
+        receiver = "webhook"
+      }
+      receiver {
+        name = "webhook"
+        webhook_configs {
+          url = "http://webhook.example.com/hook"
+          http_config {
+            oauth2 {
+              client_id = "alertmanager"
+              client_secret = "secret"
+              token_url = "https://auth.example.com/token"
+              proxy_url = "http://proxy.example.com:3128"
+              tls_config {
+                server_name = "auth.example.com"
+              }
+            }
+            tls_config {
+              max_version = "TLS13"
+            }
+          }
+        }
+      }
+    }
+`
+
+func TestAccResourceAlertmanagerConfig_TimeIntervalLocation(t *testing.T) {
+	// Init client
+	client, err := NewAPIClient(setupClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMimirAlertmanagerConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccResourceAlertmanagerConfig_TimeIntervalLocation, "mute_time_intervals", "Mars/Olympus_Mons"),
+				ExpectError: regexp.MustCompile("unknown time zone Mars/Olympus_Mons"),
+			},
+			{
+				Config: fmt.Sprintf(testAccResourceAlertmanagerConfig_TimeIntervalLocation, "mute_time_intervals", "Europe/Paris"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirAlertmanagerConfigExists("mimir_alertmanager_config.mytenant", client),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "time_intervals_key", "mute_time_intervals"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "time_interval.0.name", "business_hours"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "time_interval.0.time_intervals.0.location", "Europe/Paris"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccResourceAlertmanagerConfig_TimeIntervalLocation, "time_intervals", "America/New_York"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirAlertmanagerConfigExists("mimir_alertmanager_config.mytenant", client),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "time_intervals_key", "time_intervals"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "time_interval.0.name", "business_hours"),
+					resource.TestCheckResourceAttr("mimir_alertmanager_config.mytenant", "time_interval.0.time_intervals.0.location", "America/New_York"),
+				),
+			},
+		},
+	})
+}
+
+const testAccResourceAlertmanagerConfig_TimeIntervalLocation = `
+    resource "mimir_alertmanager_config" "mytenant" {
+      time_intervals_key = "%s"
+      time_interval {
+        name = "business_hours"
+        time_intervals {
+          weekdays {
+            begin = 1
+            end = 5
+          }
+          times {
+            start_minute = 540
+            end_minute = 1080
+          }
+          location = "%s"
+        }
+      }
+      route {
+        group_by = ["..."]
+        group_wait = "30s"
+        group_interval = "5m"
+        repeat_interval = "1h"
+        receiver = "pagerduty"
+        child_route {
+          matchers = ["severity=\"warning\""]
+          group_wait = "30s"
+          group_interval = "5m"
+          repeat_interval = "1h"
+          receiver = "pagerduty"
+          active_time_intervals = ["business_hours"]
+        }
+      }
+      receiver {
+        name = "pagerduty"
+        pagerduty_configs {
+          routing_key = "secret"
+        }
+      }
+    }
+`
+
+func TestAlertmanagerConfigDiff(t *testing.T) {
+	want := "global:\n  resolve_timeout: 5m\nroute:\n  receiver: a\n  group_wait: 30s\n"
+	got := "global:\n  resolve_timeout: 5m\nroute:\n  receiver: b\n  group_wait: 30s\ntemplates:\n  - a.tmpl\n"
+
+	expected := "  route: configured \"receiver: a\", running \"receiver: b\"\n" +
+		"  templates: configured \"<none>\", running \"- a.tmpl\""
+	if diff := alertmanagerConfigDiff(want, got); diff != expected {
+		t.Fatalf("Got %q but expected %q", diff, expected)
+	}
+}
+
+// testAlertmanagerStatus is the status returned by the alertmanager for the
+// pagerduty config of TestAlertmanagerConfigRunningDiff.
+const testAlertmanagerStatus = `{
+  "cluster": {"status": "ready", "peers": []},
+  "versionInfo": {"version": "0.24.0"},
+  "uptime": "2022-10-01T10:00:00.000Z",
+  "config": {
+    "original": "global:\n  resolve_timeout: 5m\n  http_config:\n    follow_redirects: true\n    enable_http2: true\n  smtp_hello: localhost\n  smtp_require_tls: true\n  pagerduty_url: https://events.pagerduty.com/v2/enqueue\n  opsgenie_api_url: https://api.opsgenie.com/\n  wechat_api_url: https://qyapi.weixin.qq.com/cgi-bin/\n  victorops_api_url: https://alert.victorops.com/integrations/generic/20131114/alert/\n  telegram_api_url: https://api.telegram.org\nroute:\n  receiver: pagerduty\n  group_by:\n  - alertname\n  continue: false\nreceivers:\n- name: pagerduty\n  pagerduty_configs:\n  - send_resolved: true\n    http_config:\n      follow_redirects: true\n      enable_http2: true\n    routing_key: <secret>\n    url: https://events.pagerduty.com/v2/enqueue\n    client: '{{ template \"pagerduty.default.client\" . }}'\n    client_url: '{{ template \"pagerduty.default.clientURL\" . }}'\n    description: '{{ template \"pagerduty.default.description\" .}}'\n    details:\n      firing: '{{ template \"pagerduty.default.instances\" .Alerts.Firing }}'\n      num_firing: '{{ .Alerts.Firing | len }}'\n      num_resolved: '{{ .Alerts.Resolved | len }}'\n      resolved: '{{ template \"pagerduty.default.instances\" .Alerts.Resolved }}'\n    severity: info\ntemplates: []\n"
+  }
+}`
+
+func TestAlertmanagerConfigRunningDiff(t *testing.T) {
+	var status alertmanagerStatus
+	if err := json.Unmarshal([]byte(testAlertmanagerStatus), &status); err != nil {
+		t.Fatal(err)
+	}
+
+	pagerduty := func(severity string) string {
+		out, err := yaml.Marshal(&alertmanagerConfig{
+			Route: &route{Receiver: "pagerduty", GroupByStr: []string{"alertname"}},
+			Receivers: []*receiver{{
+				Name:             "pagerduty",
+				PagerdutyConfigs: []*pagerdutyConfig{{RoutingKey: "secret", Severity: severity}},
+			}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(out)
+	}
+
+	// Jira is newer than the alertmanager library, which cannot load it.
+	jira := func(project string) string {
+		out, err := yaml.Marshal(&alertmanagerConfig{
+			Route: &route{Receiver: "jira"},
+			Receivers: []*receiver{{
+				Name:        "jira",
+				JiraConfigs: []*jiraConfig{{APIURL: "https://jira.example.com", Project: project, IssueType: "Bug"}},
+			}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(out)
+	}
+	jiraRunning := "global:\n  resolve_timeout: 5m\n  webex_api_url: https://webexapis.com/v1/messages\n" +
+		"route:\n  receiver: jira\n  continue: false\n" +
+		"receivers:\n- name: jira\n  jira_configs:\n  - send_resolved: true\n    api_url: <secret>\n    project: OPS\n" +
+		"    issue_type: Bug\n    summary: '{{ template \"jira.default.summary\" . }}'\n"
+
+	tests := []struct {
+		name     string
+		expected string
+		running  string
+		diff     string
+	}{
+		{
+			name:     "loaded",
+			expected: pagerduty("info"),
+			running:  status.Config.Original,
+		},
+		{
+			name:     "loaded changed",
+			expected: pagerduty("critical"),
+			running:  status.Config.Original,
+			diff:     "  receivers: configured \"severity: critical\", running \"severity: info\"",
+		},
+		{
+			name:     "not loaded",
+			expected: jira("OPS"),
+			running:  jiraRunning,
+		},
+		{
+			name:     "not loaded changed",
+			expected: jira("DEV"),
+			running:  jiraRunning,
+			diff:     "  receivers[0].jira_configs[0].project: configured value not running",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := alertmanagerConfigRunningDiff(tt.expected, tt.running); diff != tt.diff {
+				t.Fatalf("Got %q but expected %q", diff, tt.diff)
+			}
+		})
+	}
+}
+
+func TestJiraConfigFields(t *testing.T) {
+	fields := map[string]interface{}{
+		"customfield_10000": `{"value": "foo"}`,
+		"customfield_10001": "9007199254740993",
+		"customfield_10002": `"42"`,
+		"customfield_10003": "plain text",
+		"customfield_10004": `[1, 2.5]`,
+		"customfield_10005": `{} trailing`,
+	}
+	expected := map[string]interface{}{
+		"customfield_10000": map[string]interface{}{"value": "foo"},
+		"customfield_10001": int64(9007199254740993),
+		"customfield_10002": "42",
+		"customfield_10003": "plain text",
+		"customfield_10004": []interface{}{int64(1), 2.5},
+		"customfield_10005": `{} trailing`,
+	}
+
+	got := expandJiraConfigFields(fields)
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expandJiraConfigFields() = %#v but expected %#v", got, expected)
+	}
+
+	flattened, err := flattenJiraConfigFields(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expandJiraConfigFields(flattened), expected) {
+		t.Fatalf("flattenJiraConfigFields() = %#v does not expand back to %#v", flattened, expected)
+	}
+
+	if _, err := flattenJiraConfigFields(map[string]interface{}{"customfield_10000": make(chan int)}); err == nil {
+		t.Fatal("Expected an error for a value which cannot be encoded")
+	}
+}
+
+func TestAlertmanagerTimeIntervalsKey(t *testing.T) {
+	interval := []*muteTimeInterval{{Name: "weekends"}}
+	tests := []struct {
+		name     string
+		conf     *alertmanagerConfig
+		expected string
+		err      bool
+	}{
+		{name: "none", conf: &alertmanagerConfig{}},
+		{name: "mute_time_intervals", conf: &alertmanagerConfig{MuteTimeIntervals: interval}, expected: "mute_time_intervals"},
+		{name: "time_intervals", conf: &alertmanagerConfig{TimeIntervals: interval}, expected: "time_intervals"},
+		{name: "both", conf: &alertmanagerConfig{MuteTimeIntervals: interval, TimeIntervals: interval}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := alertmanagerTimeIntervalsKey(tt.conf)
+			if (err != nil) != tt.err {
+				t.Fatalf("Got error %v but expected an error: %v", err, tt.err)
+			}
+			if got != tt.expected {
+				t.Fatalf("Got %q but expected %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package mimir
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -16,9 +20,16 @@ func resourcemimirRuleGroupAlerting() *schema.Resource {
 		ReadContext:   resourcemimirRuleGroupAlertingRead,
 		UpdateContext: resourcemimirRuleGroupAlertingUpdate,
 		DeleteContext: resourcemimirRuleGroupAlertingDelete,
+		CustomizeDiff: resourcemimirRuleGroupAlertingCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"namespace": {
 				Type:        schema.TypeString,
@@ -74,6 +85,8 @@ func resourcemimirRuleGroupAlerting() *schema.Resource {
 					},
 				},
 			},
+			"absent_alert": absentAlertSchema(),
+			"absent_rule":  absentRuleSchema(),
 		}, /* End schema */
 	}
 }
@@ -83,21 +96,34 @@ func resourcemimirRuleGroupAlertingCreate(ctx context.Context, d *schema.Resourc
 	name := d.Get("name").(string)
 	namespace := d.Get("namespace").(string)
 
-	rules := &alertingRuleGroup{
-		Name:  name,
-		Rules: expandAlertingRules(d.Get("rule").([]interface{})),
+	rules, err := expandAlertingRuleGroup(client, d)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := checkRuleGroupDependencies(ctx, d, client); err != nil {
+		return diagFromErr(err)
+	}
+	if err := checkRuleGroupDuplicates(ctx, d, client); err != nil {
+		return diagFromErr(err)
+	}
+	if err := alertPolicyError(client.alert_policy, checkAlertPolicy(client.alert_policy, namespace, name, rules.Rules)); err != nil {
+		return diagFromErr(err)
+	}
+	if err := enforceAlertingRulesMatchers(rules.Rules, client.enforced_matchers); err != nil {
+		return diagFromErr(err)
 	}
 	data, _ := yaml.Marshal(rules)
 	headers := map[string]string{"Content-Type": "application/yaml"}
 
 	path := fmt.Sprintf("/config/v1/rules/%s", namespace)
-	jobraw, err := client.send_request("ruler", "POST", path, string(data), headers)
+	jobraw, err := client.send_request(ctx, "ruler", "POST", path, string(data), headers)
 	baseMsg := fmt.Sprintf("Cannot create alerting rule group '%s' -", name)
 	fullurl := fmt.Sprintf("%s%s", client.uri, path)
 	err = handleHTTPError(err, jobraw, fullurl, baseMsg)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
+	client.rule_groups.set(namespace, string(data))
 	d.SetId(fmt.Sprintf("%s/%s", namespace, name))
 	return resourcemimirRuleGroupAlertingRead(ctx, d, meta)
 }
@@ -105,32 +131,194 @@ func resourcemimirRuleGroupAlertingCreate(ctx context.Context, d *schema.Resourc
 func resourcemimirRuleGroupAlertingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	err := ruleAlertingRead(ctx, d, meta)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
-	return diag.Diagnostics{}
+	if d.Id() == "" {
+		return diag.Diagnostics{}
+	}
+
+	client := meta.(*api_client)
+	absent, err := expandAbsentAlert(d.Get("absent_alert").([]interface{}))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	violations, err := alertingRuleGroupPolicyViolations(client, d.Get("namespace").(string), d.Get("name").(string), d.Get("rule").([]interface{}), absent)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	diags := alertPolicyWarnings(client.alert_policy, violations)
+	diags = append(diags, ruleGroupDependencyWarnings(ctx, d, client)...)
+	return append(diags, ruleGroupDuplicateWarnings(ctx, d, client)...)
+}
+
+func resourcemimirRuleGroupAlertingCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := absentRulesCustomizeDiff(d); err != nil {
+		return err
+	}
+
+	client, ok := meta.(*api_client)
+	if !ok {
+		return nil
+	}
+	if err := ruleDependenciesCustomizeDiff(ctx, d, client); err != nil {
+		return err
+	}
+	if err := ruleDuplicatesCustomizeDiff(ctx, d, client); err != nil {
+		return err
+	}
+	if client.alert_policy == nil {
+		return nil
+	}
+	// The policy is checked on the known rules, and on all of them before
+	// they are sent to the ruler.
+	for _, key := range []string{"namespace", "name", "rule", "absent_alert"} {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+	}
+	var rules []interface{}
+	for i, rule := range d.Get("rule").([]interface{}) {
+		if d.NewValueKnown(fmt.Sprintf("rule.%d.labels.%%", i)) && d.NewValueKnown(fmt.Sprintf("rule.%d.annotations.%%", i)) {
+			rules = append(rules, rule)
+		}
+	}
+	absent, err := expandAbsentAlert(d.Get("absent_alert").([]interface{}))
+	if err != nil {
+		return err
+	}
+
+	violations, err := alertingRuleGroupPolicyViolations(client, d.Get("namespace").(string), d.Get("name").(string), rules, absent)
+	if err != nil {
+		return err
+	}
+	return alertPolicyError(client.alert_policy, violations)
+}
+
+// absentRulesCustomizeDiff computes the absent alerts generated from the rules
+// at plan time for review.
+func absentRulesCustomizeDiff(d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("rule") || !d.NewValueKnown("absent_alert") {
+		return d.SetNewComputed("absent_rule")
+	}
+	var exprs []string
+	for i, rule := range d.Get("rule").([]interface{}) {
+		if !d.NewValueKnown(fmt.Sprintf("rule.%d.expr", i)) {
+			return d.SetNewComputed("absent_rule")
+		}
+		exprs = append(exprs, rule.(map[string]interface{})["expr"].(string))
+	}
+
+	absent, err := expandAbsentAlert(d.Get("absent_alert").([]interface{}))
+	if err != nil {
+		return err
+	}
+	generated, err := generateAbsentAlertingRules(absent, exprs)
+	if err != nil {
+		return err
+	}
+
+	rules := flattenAlertingRules(generated)
+	if computedRulesEqual(d.Get("absent_rule").([]interface{}), rules) {
+		return nil
+	}
+	return d.SetNew("absent_rule", rules)
+}
+
+// alertingRuleGroupPolicyViolations checks the policy on the rules and the
+// absent alerts merged with the default labels and annotations.
+func alertingRuleGroupPolicyViolations(client *api_client, namespace, name string, v []interface{}, absent *absentAlert) ([]string, error) {
+	if client.alert_policy == nil {
+		return nil, nil
+	}
+
+	defaults := alertingRuleDefaults{
+		Namespace:   namespace,
+		Group:       name,
+		Labels:      client.default_alert_labels,
+		Annotations: client.default_alert_annotations,
+	}
+	rules, err := expandAlertingRules(v, defaults)
+	if err != nil {
+		return nil, err
+	}
+	generated, err := generateAbsentAlertingRules(absent, alertingRulesExprs(rules))
+	if err != nil {
+		return nil, err
+	}
+	if err := applyAlertingRuleDefaults(generated, defaults); err != nil {
+		return nil, err
+	}
+
+	return checkAlertPolicy(client.alert_policy, namespace, name, append(rules, generated...)), nil
+}
+
+// expandAlertingRuleGroup returns the rule group to send to the ruler, with the
+// absent alerts and the default labels and annotations.
+func expandAlertingRuleGroup(client *api_client, d resourceDataGetter) (*alertingRuleGroup, error) {
+	name := d.Get("name").(string)
+	defaults := alertingRuleDefaults{
+		Namespace:   d.Get("namespace").(string),
+		Group:       name,
+		Labels:      client.default_alert_labels,
+		Annotations: client.default_alert_annotations,
+	}
+
+	rules, err := expandAlertingRules(d.Get("rule").([]interface{}), defaults)
+	if err != nil {
+		return nil, err
+	}
+	absent, err := expandAbsentAlert(d.Get("absent_alert").([]interface{}))
+	if err != nil {
+		return nil, err
+	}
+	generated, err := generateAbsentAlertingRules(absent, alertingRulesExprs(rules))
+	if err != nil {
+		return nil, err
+	}
+	if err := applyAlertingRuleDefaults(generated, defaults); err != nil {
+		return nil, err
+	}
+
+	return &alertingRuleGroup{
+		Name:  name,
+		Rules: append(rules, generated...),
+	}, nil
 }
 
 func resourcemimirRuleGroupAlertingUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	if d.HasChange("rule") {
+	if d.HasChanges("rule", "absent_alert", "absent_rule") {
 		client := meta.(*api_client)
 		name := d.Get("name").(string)
 		namespace := d.Get("namespace").(string)
 
-		rules := &alertingRuleGroup{
-			Name:  name,
-			Rules: expandAlertingRules(d.Get("rule").([]interface{})),
+		rules, err := expandAlertingRuleGroup(client, d)
+		if err != nil {
+			return diagFromErr(err)
+		}
+		if err := checkRuleGroupDependencies(ctx, d, client); err != nil {
+			return diagFromErr(err)
+		}
+		if err := checkRuleGroupDuplicates(ctx, d, client); err != nil {
+			return diagFromErr(err)
+		}
+		if err := alertPolicyError(client.alert_policy, checkAlertPolicy(client.alert_policy, namespace, name, rules.Rules)); err != nil {
+			return diagFromErr(err)
+		}
+		if err := enforceAlertingRulesMatchers(rules.Rules, client.enforced_matchers); err != nil {
+			return diagFromErr(err)
 		}
 		data, _ := yaml.Marshal(rules)
 		headers := map[string]string{"Content-Type": "application/yaml"}
 
 		path := fmt.Sprintf("/config/v1/rules/%s", namespace)
-		jobraw, err := client.send_request("ruler", "POST", path, string(data), headers)
+		jobraw, err := client.send_request(ctx, "ruler", "POST", path, string(data), headers)
 		baseMsg := fmt.Sprintf("Cannot update alerting rule group '%s' -", name)
 		fullurl := fmt.Sprintf("%s%s", client.uri, path)
 		err = handleHTTPError(err, jobraw, fullurl, baseMsg)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
+		client.rule_groups.set(namespace, string(data))
 	}
 	return resourcemimirRuleGroupAlertingRead(ctx, d, meta)
 }
@@ -141,14 +329,15 @@ func resourcemimirRuleGroupAlertingDelete(ctx context.Context, d *schema.Resourc
 	namespace := d.Get("namespace").(string)
 	var headers map[string]string
 	path := fmt.Sprintf("/config/v1/rules/%s/%s", namespace, name)
-	_, err := client.send_request("ruler", "DELETE", path, "", headers)
+	_, err := client.send_request(ctx, "ruler", "DELETE", path, "", headers)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf(
-			"Cannot delete alerting rule group '%s' from %s: %v",
+		return diagFromErr(fmt.Errorf(
+			"Cannot delete alerting rule group '%s' from %s: %w",
 			name,
 			fmt.Sprintf("%s%s", client.uri, path),
 			err))
 	}
+	client.rule_groups.delete(namespace, name)
 	d.SetId("")
 
 	return diag.Diagnostics{}
@@ -164,13 +353,13 @@ func ruleAlertingRead(ctx context.Context, d *schema.ResourceData, meta interfac
 
 	var headers map[string]string
 	path := fmt.Sprintf("/config/v1/rules/%s/%s", namespace, name)
-	jobraw, err := client.send_request("ruler", "GET", path, "", headers)
+	jobraw, err := client.send_request(ctx, "ruler", "GET", path, "", headers)
 
 	baseMsg := fmt.Sprintf("Cannot read alerting rule group '%s' -", name)
 	fullurl := fmt.Sprintf("%s%s", client.uri, path)
 	err = handleHTTPError(err, jobraw, fullurl, baseMsg)
 	if err != nil {
-		if strings.Contains(err.Error(), "response code '404'") {
+		if isNotFoundError(err) {
 			d.SetId("")
 			return nil
 		}
@@ -183,7 +372,30 @@ func ruleAlertingRead(ctx context.Context, d *schema.ResourceData, meta interfac
 		return fmt.Errorf("Unable to decode alerting namespace rule group '%s' data: %v", name, err)
 	}
 
-	if err := d.Set("rule", flattenAlertingRules(data.Rules)); err != nil {
+	absent, err := expandAbsentAlert(d.Get("absent_alert").([]interface{}))
+	if err != nil {
+		return err
+	}
+	configured, generated := splitAbsentAlertingRules(absent, data.Rules)
+
+	defaults := alertingRuleDefaults{
+		Namespace:   namespace,
+		Group:       name,
+		Labels:      client.default_alert_labels,
+		Annotations: client.default_alert_annotations,
+	}
+	rules := flattenAlertingRules(configured)
+	restoreEnforcedExprs(d, "rule", rules, client.enforced_matchers)
+	removeAlertingRuleDefaults(d, rules, defaults)
+	if err := d.Set("rule", rules); err != nil {
+		return err
+	}
+
+	absentRules, err := readAbsentAlertingRules(absent, rules, generated, defaults, client.enforced_matchers)
+	if err != nil {
+		return err
+	}
+	if err := d.Set("absent_rule", flattenAlertingRules(absentRules)); err != nil {
 		return err
 	}
 
@@ -193,7 +405,48 @@ func ruleAlertingRead(ctx context.Context, d *schema.ResourceData, meta interfac
 	return nil
 }
 
-func expandAlertingRules(v []interface{}) []alertingRule {
+// readAbsentAlertingRules returns the absent alerts generated from the rules of
+// the state when the ruler has them, with the default labels and annotations
+// and the enforced matchers, and the absent alerts of the ruler otherwise.
+func readAbsentAlertingRules(absent *absentAlert, rules []map[string]interface{}, ruler []alertingRule, defaults alertingRuleDefaults, matchers map[string]string) ([]alertingRule, error) {
+	var exprs []string
+	for _, rule := range rules {
+		exprs = append(exprs, rule["expr"].(string))
+	}
+	generated, err := generateAbsentAlertingRules(absent, exprs)
+	if err != nil {
+		return nil, err
+	}
+
+	// The defaults and the matchers replace the labels, annotations and
+	// expression of the copied rules, leaving the generated ones untouched.
+	expected := append([]alertingRule(nil), generated...)
+	if err := applyAlertingRuleDefaults(expected, defaults); err != nil {
+		return nil, err
+	}
+	if err := enforceAlertingRulesMatchers(expected, matchers); err != nil {
+		return nil, err
+	}
+	if len(expected) != len(ruler) {
+		return ruler, nil
+	}
+	for i := range expected {
+		expected[i].For = normalizeRulesSyncDuration(expected[i].For)
+		if !reflect.DeepEqual(expected[i], alertingRule{
+			Alert:       ruler[i].Alert,
+			Expr:        ruler[i].Expr,
+			For:         normalizeRulesSyncDuration(ruler[i].For),
+			Labels:      ruler[i].Labels,
+			Annotations: ruler[i].Annotations,
+		}) {
+			return ruler, nil
+		}
+	}
+
+	return generated, nil
+}
+
+func expandAlertingRules(v []interface{}, defaults alertingRuleDefaults) ([]alertingRule, error) {
 	var rules []alertingRule
 
 	for _, v := range v {
@@ -229,7 +482,167 @@ func expandAlertingRules(v []interface{}) []alertingRule {
 		rules = append(rules, rule)
 	}
 
-	return rules
+	if err := applyAlertingRuleDefaults(rules, defaults); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// applyAlertingRuleDefaults merges the default labels and annotations into the
+// rules.
+func applyAlertingRuleDefaults(rules []alertingRule, defaults alertingRuleDefaults) error {
+	for i := range rules {
+		labels, annotations, err := renderAlertingRuleDefaults(defaults, rules[i].Alert)
+		if err != nil {
+			return err
+		}
+		rules[i].Labels = mergeAlertingRuleDefaults(rules[i].Labels, labels)
+		rules[i].Annotations = mergeAlertingRuleDefaults(rules[i].Annotations, annotations)
+	}
+
+	return nil
+}
+
+func alertingRulesExprs(rules []alertingRule) []string {
+	exprs := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		exprs = append(exprs, rule.Expr)
+	}
+	return exprs
+}
+
+// alertingRuleDefaults are the default labels and annotations of the provider
+// and the group they are merged into.
+type alertingRuleDefaults struct {
+	Namespace   string
+	Group       string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// alertingRuleTemplateData is the data available to the default labels and
+// annotations templates.
+type alertingRuleTemplateData struct {
+	Namespace string
+	Group     string
+	Alert     string
+}
+
+// newAlertingRuleDefaultTemplate parses a default label or annotation value.
+// It uses [[ ]] delimiters to not clash with the alert templates, which are
+// expanded by the ruler.
+func newAlertingRuleDefaultTemplate(text string) (*template.Template, error) {
+	return template.New("").Delims("[[", "]]").Option("missingkey=error").Parse(text)
+}
+
+func renderAlertingRuleDefaults(defaults alertingRuleDefaults, alert string) (map[string]string, map[string]string, error) {
+	data := alertingRuleTemplateData{
+		Namespace: defaults.Namespace,
+		Group:     defaults.Group,
+		Alert:     alert,
+	}
+
+	render := func(v map[string]string) (map[string]string, error) {
+		m := make(map[string]string)
+		for key, text := range v {
+			tmpl, err := newAlertingRuleDefaultTemplate(text)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid default template %q: %v", key, err)
+			}
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, data); err != nil {
+				return nil, fmt.Errorf("Cannot render default template %q: %v", key, err)
+			}
+			m[key] = buf.String()
+		}
+		return m, nil
+	}
+
+	labels, err := render(defaults.Labels)
+	if err != nil {
+		return nil, nil, err
+	}
+	annotations, err := render(defaults.Annotations)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return labels, annotations, nil
+}
+
+// mergeAlertingRuleDefaults merges the defaults into the rule values, the rule
+// values winning.
+func mergeAlertingRuleDefaults(v, defaults map[string]string) map[string]string {
+	if len(defaults) == 0 {
+		return v
+	}
+
+	m := make(map[string]string)
+	for key, val := range defaults {
+		m[key] = val
+	}
+	for key, val := range v {
+		m[key] = val
+	}
+
+	return m
+}
+
+// removeAlertingRuleDefaults removes from the rules read from the ruler the
+// default labels and annotations not set on the rules of the state, so that
+// they do not show up as drift.
+func removeAlertingRuleDefaults(d *schema.ResourceData, rules []map[string]interface{}, defaults alertingRuleDefaults) {
+	if len(defaults.Labels) == 0 && len(defaults.Annotations) == 0 {
+		return
+	}
+
+	for i, rule := range rules {
+		labels, annotations, err := renderAlertingRuleDefaults(defaults, rule["alert"].(string))
+		if err != nil {
+			continue
+		}
+
+		for key, rendered := range map[string]map[string]string{"labels": labels, "annotations": annotations} {
+			v, ok := rule[key].(map[string]string)
+			if !ok {
+				continue
+			}
+			configured := d.Get(fmt.Sprintf("rule.%d.%s", i, key)).(map[string]interface{})
+			for name, val := range rendered {
+				if _, ok := configured[name]; !ok && v[name] == val {
+					delete(v, name)
+				}
+			}
+			if len(v) == 0 {
+				delete(rule, key)
+			}
+		}
+	}
+}
+
+func validateAlertingRuleDefaultTemplates(v interface{}, k string) (ws []string, errors []error) {
+	m := v.(map[string]interface{})
+	for name, text := range m {
+		if _, err := newAlertingRuleDefaultTemplate(text.(string)); err != nil {
+			errors = append(errors, fmt.Errorf(
+				"\"%s\": Invalid template for %q: %v", k, name, err))
+		}
+	}
+	return
+}
+
+// enforceAlertingRulesMatchers sets the enforced matchers on the rule expressions.
+func enforceAlertingRulesMatchers(rules []alertingRule, matchers map[string]string) error {
+	for i := range rules {
+		expr, err := enforceMatchers(rules[i].Expr, matchers)
+		if err != nil {
+			return err
+		}
+		rules[i].Expr = expr
+	}
+
+	return nil
 }
 
 func flattenAlertingRules(v []alertingRule) []map[string]interface{} {
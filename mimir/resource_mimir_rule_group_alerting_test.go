@@ -1,10 +1,14 @@
 package mimir
 
 import (
+	"context"
+	"fmt"
 	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gopkg.in/yaml.v3"
 )
 
 func TestAccResourceRuleGroupAlerting_expectValidationError(t *testing.T) {
@@ -189,3 +193,294 @@ const testAccResourceRuleGroupAlerting_basic_update = `
 		}
 	}
 `
+
+func TestAccResourceRuleGroupAlerting_EnforcedMatchers(t *testing.T) {
+	// Init client
+	client, err := NewAPIClient(setupClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMimirRuleGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceRuleGroupAlerting_enforcedMatchers,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirRuleGroupExists("mimir_rule_group_alerting.alert_1", "alert_1", client),
+					resource.TestCheckResourceAttr("mimir_rule_group_alerting.alert_1", "rule.0.expr", "sum by (job) (rate(test1_metric{cluster=\"us-1\"}[5m])) > 0"),
+					testAccCheckMimirRuleGroupExpr("namespace_1", "alert_1", `sum by (job) (rate(test1_metric{cluster="eu-1"}[5m])) > 0`, client),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckMimirRuleGroupExpr(namespace, name, expected string, client *api_client) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		var headers map[string]string
+		path := fmt.Sprintf("/config/v1/rules/%s/%s", namespace, name)
+		jobraw, err := client.send_request(context.Background(), "ruler", "GET", path, "", headers)
+		if err != nil {
+			return err
+		}
+
+		var data alertingRuleGroup
+		if err := yaml.Unmarshal([]byte(jobraw), &data); err != nil {
+			return err
+		}
+		if len(data.Rules) == 0 || data.Rules[0].Expr != expected {
+			return fmt.Errorf("expected rule expression %q, got %+v", expected, data.Rules)
+		}
+
+		return nil
+	}
+}
+
+const testAccResourceRuleGroupAlerting_enforcedMatchers = `
+	provider "mimir" {
+		enforced_matchers = {
+			cluster = "eu-1"
+		}
+	}
+
+	resource "mimir_rule_group_alerting" "alert_1" {
+		name = "alert_1"
+		namespace = "namespace_1"
+		rule {
+			alert = "test1"
+			expr  = "sum by (job) (rate(test1_metric{cluster=\"us-1\"}[5m])) > 0"
+		}
+	}
+`
+
+func TestAccResourceRuleGroupAlerting_DefaultLabelsAndAnnotations(t *testing.T) {
+	// Init client
+	client, err := NewAPIClient(setupClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMimirRuleGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceRuleGroupAlerting_defaults,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirRuleGroupExists("mimir_rule_group_alerting.alert_1", "alert_1", client),
+					resource.TestCheckResourceAttr("mimir_rule_group_alerting.alert_1", "rule.0.labels.%", "1"),
+					resource.TestCheckResourceAttr("mimir_rule_group_alerting.alert_1", "rule.0.labels.team", "db"),
+					resource.TestCheckResourceAttr("mimir_rule_group_alerting.alert_1", "rule.0.annotations.%", "0"),
+					testAccCheckMimirRuleGroupDefaults("namespace_1", "alert_1", client),
+				),
+			},
+			{
+				Config:      testAccResourceRuleGroupAlerting_defaultsInvalidTemplate,
+				ExpectError: regexp.MustCompile("Invalid template for \"runbook_url\""),
+			},
+		},
+	})
+}
+
+func testAccCheckMimirRuleGroupDefaults(namespace, name string, client *api_client) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		var headers map[string]string
+		path := fmt.Sprintf("/config/v1/rules/%s/%s", namespace, name)
+		jobraw, err := client.send_request(context.Background(), "ruler", "GET", path, "", headers)
+		if err != nil {
+			return err
+		}
+
+		var data alertingRuleGroup
+		if err := yaml.Unmarshal([]byte(jobraw), &data); err != nil {
+			return err
+		}
+		if len(data.Rules) == 0 {
+			return fmt.Errorf("rule group '%s' has no rule", name)
+		}
+
+		rule := data.Rules[0]
+		if rule.Labels["team"] != "db" || rule.Labels["env"] != "test" {
+			return fmt.Errorf("unexpected labels %v", rule.Labels)
+		}
+		if rule.Annotations["runbook_url"] != "https://runbooks/namespace_1/alert_1/test1" {
+			return fmt.Errorf("unexpected annotations %v", rule.Annotations)
+		}
+
+		return nil
+	}
+}
+
+const testAccResourceRuleGroupAlerting_defaults = `
+	provider "mimir" {
+		default_alert_labels = {
+			team = "sre"
+			env  = "test"
+		}
+		default_alert_annotations = {
+			runbook_url = "https://runbooks/[[ .Namespace ]]/[[ .Group ]]/[[ .Alert ]]"
+		}
+	}
+
+	resource "mimir_rule_group_alerting" "alert_1" {
+		name = "alert_1"
+		namespace = "namespace_1"
+		rule {
+			alert = "test1"
+			expr  = "test1_metric"
+			labels = {
+				team = "db"
+			}
+		}
+	}
+`
+
+const testAccResourceRuleGroupAlerting_defaultsInvalidTemplate = `
+	provider "mimir" {
+		default_alert_annotations = {
+			runbook_url = "https://runbooks/[[ .Alert "
+		}
+	}
+
+	resource "mimir_rule_group_alerting" "alert_1" {
+		name = "alert_1"
+		namespace = "namespace_1"
+		rule {
+			alert = "test1"
+			expr  = "test1_metric"
+		}
+	}
+`
+
+func TestAccResourceRuleGroupAlerting_AlertPolicy(t *testing.T) {
+	// Init client
+	client, err := NewAPIClient(setupClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMimirRuleGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccResourceRuleGroupAlerting_alertPolicy, "error", "page", "runbooks/test1"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`alert "test1" of rule group 'namespace_1/alert_1': label "severity" value "page" is not one of`),
+			},
+			{
+				Config:      fmt.Sprintf(testAccResourceRuleGroupAlerting_alertPolicy, "error", "critical", "runbooks/test1"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`annotation "runbook_url" value "runbooks/test1" is not an absolute http or https URL`),
+			},
+			{
+				Config: fmt.Sprintf(testAccResourceRuleGroupAlerting_alertPolicy, "error", "critical", "https://runbooks/test1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirRuleGroupExists("mimir_rule_group_alerting.alert_1", "alert_1", client),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccResourceRuleGroupAlerting_alertPolicy, "warning", "page", "https://runbooks/test1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mimir_rule_group_alerting.alert_1", "rule.0.labels.severity", "page"),
+				),
+			},
+		},
+	})
+}
+
+const testAccResourceRuleGroupAlerting_alertPolicy = `
+	provider "mimir" {
+		alert_policy {
+			level                = "%s"
+			required_labels      = ["severity"]
+			required_annotations = ["summary", "runbook_url"]
+			allowed_values {
+				label  = "severity"
+				values = ["critical", "warning", "info"]
+			}
+			url_annotations = ["runbook_url"]
+		}
+	}
+
+	resource "mimir_rule_group_alerting" "alert_1" {
+		name = "alert_1"
+		namespace = "namespace_1"
+		rule {
+			alert = "test1"
+			expr  = "test1_metric"
+			labels = {
+				severity = "%s"
+			}
+			annotations = {
+				summary     = "test1 is firing"
+				runbook_url = "%s"
+			}
+		}
+	}
+`
+
+func TestAccResourceRuleGroupAlerting_AbsentAlert(t *testing.T) {
+	// Init client
+	client, err := NewAPIClient(setupClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMimirRuleGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceRuleGroupAlerting_absentAlert,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMimirRuleGroupExists("mimir_rule_group_alerting.alert_1", "alert_1", client),
+					resource.TestCheckResourceAttr("mimir_rule_group_alerting.alert_1", "rule.#", "2"),
+					resource.TestCheckResourceAttr("mimir_rule_group_alerting.alert_1", "absent_rule.#", "2"),
+					resource.TestCheckResourceAttr("mimir_rule_group_alerting.alert_1", "absent_rule.0.alert", "MetricAbsent"),
+					resource.TestCheckResourceAttr("mimir_rule_group_alerting.alert_1", "absent_rule.0.expr", `absent_over_time(test1_metric{job="test"}[15m])`),
+					resource.TestCheckResourceAttr("mimir_rule_group_alerting.alert_1", "absent_rule.0.for", "10m"),
+					resource.TestCheckResourceAttr("mimir_rule_group_alerting.alert_1", "absent_rule.0.labels.metric", "test1_metric"),
+					resource.TestCheckResourceAttr("mimir_rule_group_alerting.alert_1", "absent_rule.0.labels.severity", "warning"),
+					resource.TestCheckResourceAttr("mimir_rule_group_alerting.alert_1", "absent_rule.1.expr", `absent_over_time(test2_metric[15m])`),
+				),
+			},
+			{
+				Config: testAccResourceRuleGroupAlerting_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mimir_rule_group_alerting.alert_1", "rule.#", "1"),
+					resource.TestCheckResourceAttr("mimir_rule_group_alerting.alert_1", "absent_rule.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+const testAccResourceRuleGroupAlerting_absentAlert = `
+	resource "mimir_rule_group_alerting" "alert_1" {
+		name = "alert_1"
+		namespace = "namespace_1"
+		rule {
+			alert = "test1"
+			expr  = "rate(test1_metric{job=\"test\"}[5m]) > 1"
+		}
+		rule {
+			alert = "test2"
+			expr  = "test2_metric > ignored_metric or absent(test3_metric)"
+		}
+		absent_alert {
+			for     = "10m"
+			range   = "15m"
+			exclude = ["ignored_.*"]
+			labels = {
+				severity = "warning"
+			}
+		}
+	}
+`
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -16,9 +17,16 @@ func resourcemimirRuleGroupRecording() *schema.Resource {
 		ReadContext:   resourcemimirRuleGroupRecordingRead,
 		UpdateContext: resourcemimirRuleGroupRecordingUpdate,
 		DeleteContext: resourcemimirRuleGroupRecordingDelete,
+		CustomizeDiff: resourcemimirRuleGroupRecordingCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"namespace": {
 				Type:        schema.TypeString,
@@ -51,6 +59,13 @@ func resourcemimirRuleGroupRecording() *schema.Resource {
 							Description:  "The PromQL expression to evaluate.",
 							ValidateFunc: validatePromQLExpr,
 						},
+						"labels": {
+							Type:         schema.TypeMap,
+							Description:  "Labels to add or overwrite before storing the result.",
+							Optional:     true,
+							Elem:         &schema.Schema{Type: schema.TypeString},
+							ValidateFunc: validateLabels,
+						},
 					},
 				},
 			},
@@ -67,17 +82,27 @@ func resourcemimirRuleGroupRecordingCreate(ctx context.Context, d *schema.Resour
 		Name:  name,
 		Rules: expandRecordingRules(d.Get("rule").([]interface{})),
 	}
+	if err := checkRuleGroupDependencies(ctx, d, client); err != nil {
+		return diagFromErr(err)
+	}
+	if err := checkRuleGroupDuplicates(ctx, d, client); err != nil {
+		return diagFromErr(err)
+	}
+	if err := enforceRecordingRulesMatchers(rules.Rules, client.enforced_matchers); err != nil {
+		return diagFromErr(err)
+	}
 	data, _ := yaml.Marshal(rules)
 	headers := map[string]string{"Content-Type": "application/yaml"}
 
 	path := fmt.Sprintf("/config/v1/rules/%s", namespace)
-	jobraw, err := client.send_request("ruler", "POST", path, string(data), headers)
+	jobraw, err := client.send_request(ctx, "ruler", "POST", path, string(data), headers)
 	baseMsg := fmt.Sprintf("Cannot create recording rule group '%s' -", name)
 	fullurl := fmt.Sprintf("%s%s", client.uri, path)
 	err = handleHTTPError(err, jobraw, fullurl, baseMsg)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
+	client.rule_groups.set(namespace, string(data))
 	d.SetId(fmt.Sprintf("%s/%s", namespace, name))
 	return resourcemimirRuleGroupRecordingRead(ctx, d, meta)
 }
@@ -85,9 +110,24 @@ func resourcemimirRuleGroupRecordingCreate(ctx context.Context, d *schema.Resour
 func resourcemimirRuleGroupRecordingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	err := ruleRecordingRead(ctx, d, meta)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
-	return diag.Diagnostics{}
+	if d.Id() == "" {
+		return diag.Diagnostics{}
+	}
+	client := meta.(*api_client)
+	return append(ruleGroupDependencyWarnings(ctx, d, client), ruleGroupDuplicateWarnings(ctx, d, client)...)
+}
+
+func resourcemimirRuleGroupRecordingCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	client, ok := meta.(*api_client)
+	if !ok {
+		return nil
+	}
+	if err := ruleDependenciesCustomizeDiff(ctx, d, client); err != nil {
+		return err
+	}
+	return ruleDuplicatesCustomizeDiff(ctx, d, client)
 }
 
 func resourcemimirRuleGroupRecordingUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -100,17 +140,27 @@ func resourcemimirRuleGroupRecordingUpdate(ctx context.Context, d *schema.Resour
 			Name:  name,
 			Rules: expandRecordingRules(d.Get("rule").([]interface{})),
 		}
+		if err := checkRuleGroupDependencies(ctx, d, client); err != nil {
+			return diagFromErr(err)
+		}
+		if err := checkRuleGroupDuplicates(ctx, d, client); err != nil {
+			return diagFromErr(err)
+		}
+		if err := enforceRecordingRulesMatchers(rules.Rules, client.enforced_matchers); err != nil {
+			return diagFromErr(err)
+		}
 		data, _ := yaml.Marshal(rules)
 		headers := map[string]string{"Content-Type": "application/yaml"}
 
 		path := fmt.Sprintf("/config/v1/rules/%s", namespace)
-		jobraw, err := client.send_request("ruler", "POST", path, string(data), headers)
+		jobraw, err := client.send_request(ctx, "ruler", "POST", path, string(data), headers)
 		baseMsg := fmt.Sprintf("Cannot update recording rule group '%s' -", name)
 		fullurl := fmt.Sprintf("%s%s", client.uri, path)
 		err = handleHTTPError(err, jobraw, fullurl, baseMsg)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
+		client.rule_groups.set(namespace, string(data))
 	}
 	return resourcemimirRuleGroupRecordingRead(ctx, d, meta)
 }
@@ -121,14 +171,15 @@ func resourcemimirRuleGroupRecordingDelete(ctx context.Context, d *schema.Resour
 	namespace := d.Get("namespace").(string)
 	var headers map[string]string
 	path := fmt.Sprintf("/config/v1/rules/%s/%s", namespace, name)
-	_, err := client.send_request("ruler", "DELETE", path, "", headers)
+	_, err := client.send_request(ctx, "ruler", "DELETE", path, "", headers)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf(
-			"Cannot delete recording rule group '%s' from %s: %v",
+		return diagFromErr(fmt.Errorf(
+			"Cannot delete recording rule group '%s' from %s: %w",
 			name,
 			fmt.Sprintf("%s%s", client.uri, path),
 			err))
 	}
+	client.rule_groups.delete(namespace, name)
 	d.SetId("")
 
 	return diag.Diagnostics{}
@@ -144,13 +195,13 @@ func ruleRecordingRead(ctx context.Context, d *schema.ResourceData, meta interfa
 
 	var headers map[string]string
 	path := fmt.Sprintf("/config/v1/rules/%s/%s", namespace, name)
-	jobraw, err := client.send_request("ruler", "GET", path, "", headers)
+	jobraw, err := client.send_request(ctx, "ruler", "GET", path, "", headers)
 
 	baseMsg := fmt.Sprintf("Cannot read recording rule group '%s' -", name)
 	fullurl := fmt.Sprintf("%s%s", client.uri, path)
 	err = handleHTTPError(err, jobraw, fullurl, baseMsg)
 	if err != nil {
-		if strings.Contains(err.Error(), "response code '404'") {
+		if isNotFoundError(err) {
 			d.SetId("")
 			return nil
 		}
@@ -163,7 +214,9 @@ func ruleRecordingRead(ctx context.Context, d *schema.ResourceData, meta interfa
 		return fmt.Errorf("Unable to decode recording namespace rule group '%s' data: %v", name, err)
 	}
 
-	if err := d.Set("rule", flattenRecordingRules(data.Rules)); err != nil {
+	rules := flattenRecordingRules(data.Rules)
+	restoreEnforcedExprs(d, "rule", rules, client.enforced_matchers)
+	if err := d.Set("rule", rules); err != nil {
 		return err
 	}
 
@@ -188,12 +241,29 @@ func expandRecordingRules(v []interface{}) []recordingRule {
 			rule.Expr = raw.(string)
 		}
 
+		if raw, ok := data["labels"]; ok && len(raw.(map[string]interface{})) > 0 {
+			rule.Labels = expandStringMap(raw.(map[string]interface{}))
+		}
+
 		rules = append(rules, rule)
 	}
 
 	return rules
 }
 
+// enforceRecordingRulesMatchers sets the enforced matchers on the rule expressions.
+func enforceRecordingRulesMatchers(rules []recordingRule, matchers map[string]string) error {
+	for i := range rules {
+		expr, err := enforceMatchers(rules[i].Expr, matchers)
+		if err != nil {
+			return err
+		}
+		rules[i].Expr = expr
+	}
+
+	return nil
+}
+
 func flattenRecordingRules(v []recordingRule) []map[string]interface{} {
 	var rules []map[string]interface{}
 
@@ -206,6 +276,10 @@ func flattenRecordingRules(v []recordingRule) []map[string]interface{} {
 		rule["record"] = v.Record
 		rule["expr"] = v.Expr
 
+		if v.Labels != nil {
+			rule["labels"] = v.Labels
+		}
+
 		rules = append(rules, rule)
 
 	}
@@ -225,8 +299,9 @@ func validateRecordingRuleName(v interface{}, k string) (ws []string, errors []e
 }
 
 type recordingRule struct {
-	Record string `json:"record"`
-	Expr   string `json:"expr"`
+	Record string            `json:"record"`
+	Expr   string            `json:"expr"`
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
 }
 
 type recordingRuleGroup struct {
@@ -91,6 +91,7 @@ func TestAccResourceRuleGroupRecording_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr("mimir_rule_group_recording.record_1", "rule.0.expr", "test1_metric"),
 					resource.TestCheckResourceAttr("mimir_rule_group_recording.record_1", "rule.1.record", "test2_info"),
 					resource.TestCheckResourceAttr("mimir_rule_group_recording.record_1", "rule.1.expr", "test2_metric"),
+					resource.TestCheckResourceAttr("mimir_rule_group_recording.record_1", "rule.1.labels.team", "sre"),
 				),
 			},
 		},
@@ -119,6 +120,9 @@ const testAccResourceRuleGroupRecording_basic_update = `
 		rule {
 			record = "test2_info"
 			expr   = "test2_metric"
+			labels = {
+				team = "sre"
+			}
 		}
 	}
 `
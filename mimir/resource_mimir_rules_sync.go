@@ -0,0 +1,442 @@
+package mimir
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+)
+
+func resourcemimirRulesSync() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourcemimirRulesSyncCreate,
+		ReadContext:   resourcemimirRulesSyncRead,
+		UpdateContext: resourcemimirRulesSyncUpdate,
+		DeleteContext: resourcemimirRulesSyncDelete,
+		CustomizeDiff: resourcemimirRulesSyncCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"path": {
+				Type:        schema.TypeString,
+				Description: "Directory containing the rule files (`*.yml` and `*.yaml`) or glob pattern matching them.",
+				Required:    true,
+			},
+			"namespace": {
+				Type:         schema.TypeString,
+				Description:  "Namespace of the rule files which neither declare a namespace nor are in `namespace_mapping`.",
+				Optional:     true,
+				Default:      "default",
+				ValidateFunc: validateGroupRuleName,
+			},
+			"namespace_mapping": {
+				Type:        schema.TypeMap,
+				Description: "Namespace of the rule files by file name, e.g. `{ \"node.yaml\" = \"infra\" }`. It has precedence over the namespace declared in the files.",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateGroupRuleName,
+				},
+			},
+			"groups": {
+				Type:        schema.TypeMap,
+				Description: "Rule groups definition by `<namespace>/<group name>`.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		}, /* End schema */
+	}
+}
+
+func resourcemimirRulesSyncCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := rulesSyncApply(ctx, d, meta); err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(d.Get("path").(string))
+	return resourcemimirRulesSyncRead(ctx, d, meta)
+}
+
+func resourcemimirRulesSyncRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*api_client)
+
+	// Only the namespaces the rule files were synced to are managed, any
+	// other group found in them is drift and will be deleted.
+	namespaces := make(map[string]bool)
+	for key := range d.Get("groups").(map[string]interface{}) {
+		namespaces[strings.SplitN(key, "/", 2)[0]] = true
+	}
+
+	old := d.Get("groups").(map[string]interface{})
+	groups := make(map[string]interface{})
+	for namespace := range namespaces {
+		var headers map[string]string
+		path := fmt.Sprintf("/config/v1/rules/%s", namespace)
+		jobraw, err := client.send_request(ctx, "ruler", "GET", path, "", headers)
+
+		baseMsg := fmt.Sprintf("Cannot read rule groups of namespace '%s' -", namespace)
+		fullurl := fmt.Sprintf("%s%s", client.uri, path)
+		err = handleHTTPError(err, jobraw, fullurl, baseMsg)
+		if err != nil {
+			if isNotFoundError(err) {
+				continue
+			}
+			return diagFromErr(err)
+		}
+
+		var data map[string][]rulesFileGroup
+		if err := yaml.Unmarshal([]byte(jobraw), &data); err != nil {
+			return diag.Errorf("Unable to decode rule groups of namespace '%s': %v", namespace, err)
+		}
+
+		for _, group := range data[namespace] {
+			content, err := marshalRulesSyncGroup(group)
+			if err != nil {
+				return diagFromErr(err)
+			}
+			key := rulesSyncGroupKey(namespace, group.Name)
+			groups[key] = content
+
+			// Keep the group of the state when the ruler returns it with
+			// the enforced matchers.
+			if raw, ok := old[key]; ok && len(client.enforced_matchers) > 0 {
+				enforced, err := enforceRulesSyncGroupMatchers(raw.(string), client.enforced_matchers)
+				if err == nil && enforced == content {
+					groups[key] = raw
+				}
+			}
+		}
+	}
+
+	if err := d.Set("groups", groups); err != nil {
+		return diagFromErr(err)
+	}
+
+	return alertPolicyWarnings(client.alert_policy, rulesSyncPolicyViolations(client.alert_policy, groups))
+}
+
+func resourcemimirRulesSyncUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// Moving the rule files only syncs the groups which differ, by name, so
+	// that the other ones are neither deleted nor recreated.
+	if d.HasChange("groups") {
+		if err := rulesSyncApply(ctx, d, meta); err != nil {
+			return diagFromErr(err)
+		}
+	}
+	if d.HasChange("path") {
+		d.SetId(d.Get("path").(string))
+	}
+	return resourcemimirRulesSyncRead(ctx, d, meta)
+}
+
+func resourcemimirRulesSyncDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*api_client)
+
+	for key := range d.Get("groups").(map[string]interface{}) {
+		if err := rulesSyncDeleteGroup(ctx, client, key); err != nil {
+			return diagFromErr(err)
+		}
+	}
+	d.SetId("")
+
+	return diag.Diagnostics{}
+}
+
+func resourcemimirRulesSyncCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	// Rule files are read at plan time so that created, updated and
+	// deleted groups show up in the plan.
+	for _, key := range []string{"path", "namespace", "namespace_mapping"} {
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("groups")
+		}
+	}
+
+	groups, err := loadRulesSyncGroups(
+		d.Get("path").(string),
+		d.Get("namespace").(string),
+		d.Get("namespace_mapping").(map[string]interface{}),
+	)
+	if err != nil {
+		return err
+	}
+
+	if client, ok := meta.(*api_client); ok {
+		if err := alertPolicyError(client.alert_policy, rulesSyncPolicyViolations(client.alert_policy, groups)); err != nil {
+			return err
+		}
+	}
+
+	old := d.Get("groups").(map[string]interface{})
+	if len(old) == len(groups) {
+		changed := false
+		for key, content := range groups {
+			if old[key] != content {
+				changed = true
+				break
+			}
+		}
+		if !changed {
+			return nil
+		}
+	}
+
+	return d.SetNew("groups", groups)
+}
+
+// rulesSyncApply creates or updates the groups which changed and deletes the
+// ones which are no longer defined in the rule files.
+func rulesSyncApply(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*api_client)
+
+	o, n := d.GetChange("groups")
+	oldGroups := o.(map[string]interface{})
+	newGroups := n.(map[string]interface{})
+
+	keys := make([]string, 0, len(newGroups))
+	for key := range newGroups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		content := newGroups[key].(string)
+		if oldGroups[key] == content {
+			continue
+		}
+
+		content, err := enforceRulesSyncGroupMatchers(content, client.enforced_matchers)
+		if err != nil {
+			return err
+		}
+
+		namespace, name := splitRulesSyncGroupKey(key)
+		headers := map[string]string{"Content-Type": "application/yaml"}
+		path := fmt.Sprintf("/config/v1/rules/%s", namespace)
+		jobraw, err := client.send_request(ctx, "ruler", "POST", path, content, headers)
+		baseMsg := fmt.Sprintf("Cannot sync rule group '%s' -", name)
+		fullurl := fmt.Sprintf("%s%s", client.uri, path)
+		err = handleHTTPError(err, jobraw, fullurl, baseMsg)
+		if err != nil {
+			return err
+		}
+		client.rule_groups.set(namespace, content)
+	}
+
+	for key := range oldGroups {
+		if _, ok := newGroups[key]; ok {
+			continue
+		}
+		if err := rulesSyncDeleteGroup(ctx, client, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func rulesSyncDeleteGroup(ctx context.Context, client *api_client, key string) error {
+	namespace, name := splitRulesSyncGroupKey(key)
+
+	var headers map[string]string
+	path := fmt.Sprintf("/config/v1/rules/%s/%s", namespace, name)
+	_, err := client.send_request(ctx, "ruler", "DELETE", path, "", headers)
+	if err != nil && !isNotFoundError(err) {
+		return fmt.Errorf(
+			"Cannot delete rule group '%s' from %s: %w",
+			name,
+			fmt.Sprintf("%s%s", client.uri, path),
+			err)
+	}
+	client.rule_groups.delete(namespace, name)
+
+	return nil
+}
+
+// loadRulesSyncGroups reads and validates the rule files matched by path and
+// returns their groups by namespace and name.
+func loadRulesSyncGroups(path, namespace string, mapping map[string]interface{}) (map[string]interface{}, error) {
+	files, err := matchRuleFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string]interface{})
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read rule file '%s': %v", file, err)
+		}
+
+		data, err := parseRulesFile(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("Unable to decode rule file '%s': %v", file, err)
+		}
+
+		if diags := validateRulesFile(data); diags.HasError() {
+			var details []string
+			for _, d := range diags {
+				details = append(details, d.Detail)
+			}
+			return nil, fmt.Errorf("Invalid rule file '%s':\n%s", file, strings.Join(details, "\n"))
+		}
+
+		ns := namespace
+		if data.Namespace != "" {
+			ns = data.Namespace
+		}
+		if raw, ok := mapping[filepath.Base(file)]; ok {
+			ns = raw.(string)
+		}
+
+		for _, group := range data.Groups {
+			key := rulesSyncGroupKey(ns, group.Name)
+			if _, ok := groups[key]; ok {
+				return nil, fmt.Errorf("Duplicate rule group '%s' in namespace '%s' found in rule file '%s'", group.Name, ns, file)
+			}
+
+			groups[key], err = marshalRulesSyncGroup(group)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return groups, nil
+}
+
+// matchRuleFiles returns the rule files of a directory or matched by a glob
+// pattern.
+func matchRuleFiles(path string) ([]string, error) {
+	var files []string
+
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		for _, ext := range []string{"*.yml", "*.yaml"} {
+			matches, _ := filepath.Glob(filepath.Join(path, ext))
+			files = append(files, matches...)
+		}
+	} else {
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid rule files pattern '%s': %v", path, err)
+		}
+		files = matches
+	}
+
+	// Refuse to sync nothing, which would delete every managed group.
+	if len(files) == 0 {
+		return nil, fmt.Errorf("No rule file found in '%s'", path)
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// marshalRulesSyncGroup returns the canonical definition of a rule group, so
+// that groups read from the rule files and from the ruler compare equal.
+func marshalRulesSyncGroup(group rulesFileGroup) (string, error) {
+	group.Interval = normalizeRulesSyncDuration(group.Interval)
+	for i := range group.Rules {
+		group.Rules[i].For = normalizeRulesSyncDuration(group.Rules[i].For)
+	}
+
+	content, err := yaml.Marshal(&group)
+	if err != nil {
+		return "", fmt.Errorf("Cannot marshal rule group '%s': %v", group.Name, err)
+	}
+
+	return string(content), nil
+}
+
+// rulesSyncPolicyViolations checks the alert policy on the alerting rules of
+// the groups.
+func rulesSyncPolicyViolations(policy *alertPolicy, groups map[string]interface{}) []string {
+	var violations []string
+
+	if policy == nil {
+		return violations
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var group rulesFileGroup
+		if err := yaml.Unmarshal([]byte(groups[key].(string)), &group); err != nil {
+			continue
+		}
+
+		var rules []alertingRule
+		for _, r := range group.Rules {
+			if r.Alert != "" {
+				rules = append(rules, alertingRule{
+					Alert:       r.Alert,
+					Labels:      r.Labels,
+					Annotations: r.Annotations,
+				})
+			}
+		}
+
+		namespace, _ := splitRulesSyncGroupKey(key)
+		violations = append(violations, checkAlertPolicy(policy, namespace, group.Name, rules)...)
+	}
+
+	return violations
+}
+
+// enforceRulesSyncGroupMatchers sets the enforced matchers on the rule
+// expressions of a group definition.
+func enforceRulesSyncGroupMatchers(content string, matchers map[string]string) (string, error) {
+	if len(matchers) == 0 {
+		return content, nil
+	}
+
+	var group rulesFileGroup
+	if err := yaml.Unmarshal([]byte(content), &group); err != nil {
+		return "", fmt.Errorf("Unable to decode rule group: %v", err)
+	}
+
+	for i := range group.Rules {
+		expr, err := enforceMatchers(group.Rules[i].Expr, matchers)
+		if err != nil {
+			return "", err
+		}
+		group.Rules[i].Expr = expr
+	}
+
+	return marshalRulesSyncGroup(group)
+}
+
+func normalizeRulesSyncDuration(v string) string {
+	d, err := model.ParseDuration(v)
+	if err != nil {
+		return v
+	}
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+func rulesSyncGroupKey(namespace, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
+
+func splitRulesSyncGroupKey(key string) (string, string) {
+	parts := strings.SplitN(key, "/", 2)
+	return parts[0], parts[1]
+}
@@ -0,0 +1,149 @@
+package mimir
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccResourceRulesSync_Basic(t *testing.T) {
+	dir := t.TempDir()
+	moved := t.TempDir()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMimirRulesSyncDestroy,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					testAccWriteRuleFile(t, dir, "node.yaml", testAccResourceRulesSync_node)
+					testAccWriteRuleFile(t, dir, "team.yml", testAccResourceRulesSync_team)
+				},
+				Config: fmt.Sprintf(testAccResourceRulesSync_basic, dir),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mimir_rules_sync.rules", "groups.%", "2"),
+					resource.TestMatchResourceAttr("mimir_rules_sync.rules", "groups.infra/node", regexp.MustCompile(`(?m)^interval: 1m\n`)),
+					resource.TestMatchResourceAttr("mimir_rules_sync.rules", "groups.team_1/team", regexp.MustCompile(`(?m)record: team:up:sum$`)),
+				),
+			},
+			{
+				PreConfig: func() {
+					os.Remove(filepath.Join(dir, "team.yml"))
+				},
+				Config: fmt.Sprintf(testAccResourceRulesSync_basic, dir),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mimir_rules_sync.rules", "groups.%", "1"),
+					resource.TestCheckResourceAttrSet("mimir_rules_sync.rules", "groups.infra/node"),
+				),
+			},
+			{
+				PreConfig: func() {
+					testAccWriteRuleFile(t, moved, "node.yaml", testAccResourceRulesSync_node)
+				},
+				Config: fmt.Sprintf(testAccResourceRulesSync_basic, moved),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mimir_rules_sync.rules", "id", moved),
+					resource.TestCheckResourceAttr("mimir_rules_sync.rules", "groups.%", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceRulesSync_expectValidationError(t *testing.T) {
+	dir := t.TempDir()
+	testAccWriteRuleFile(t, dir, "node.yaml", testAccResourceRulesSync_invalid)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccResourceRulesSync_basic, dir),
+				ExpectError: regexp.MustCompile("Invalid PromQL expression"),
+			},
+			{
+				Config:      fmt.Sprintf(testAccResourceRulesSync_basic, filepath.Join(dir, "missing")),
+				ExpectError: regexp.MustCompile("No rule file found"),
+			},
+		},
+	})
+}
+
+func testAccWriteRuleFile(t *testing.T, dir, name, content string) {
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testAccCheckMimirRulesSyncDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*api_client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "mimir_rules_sync" {
+			continue
+		}
+
+		for key := range rs.Primary.Attributes {
+			if !strings.HasPrefix(key, "groups.") || key == "groups.%" {
+				continue
+			}
+			namespace, name := splitRulesSyncGroupKey(strings.TrimPrefix(key, "groups."))
+
+			var headers map[string]string
+			path := fmt.Sprintf("/config/v1/rules/%s/%s", namespace, name)
+			_, err := client.send_request(context.Background(), "ruler", "GET", path, "", headers)
+			if err == nil {
+				return fmt.Errorf("rule group '%s' still exists", key)
+			}
+			if !isNotFoundError(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+const testAccResourceRulesSync_basic = `
+	resource "mimir_rules_sync" "rules" {
+		path = "%s"
+		namespace_mapping = {
+			"team.yml" = "team_1"
+		}
+	}
+`
+
+const testAccResourceRulesSync_node = `namespace: infra
+groups:
+  - name: node
+    interval: 60s
+    rules:
+      - record: instance:up:sum
+        expr: sum by (instance) (up)
+      - alert: InstanceDown
+        expr: instance:up:sum == 0
+        for: 5m
+`
+
+const testAccResourceRulesSync_team = `groups:
+  - name: team
+    rules:
+      - record: team:up:sum
+        expr: sum(up)
+`
+
+const testAccResourceRulesSync_invalid = `groups:
+  - name: node
+    rules:
+      - record: instance:up:sum
+        expr: sum by (instance (up)
+`
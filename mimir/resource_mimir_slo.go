@@ -0,0 +1,746 @@
+package mimir
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+)
+
+// sloDefaultBurnRateAlerts are the multiwindow, multi-burn-rate alerts
+// recommended by the Google SRE workbook for a 30 days window.
+var sloDefaultBurnRateAlerts = []sloBurnRateAlert{
+	{LongWindow: "1h", ShortWindow: "5m", BurnRate: 14.4, Severity: "page"},
+	{LongWindow: "6h", ShortWindow: "30m", BurnRate: 6, Severity: "page"},
+	{LongWindow: "1d", ShortWindow: "2h", BurnRate: 3, Severity: "ticket"},
+	{LongWindow: "3d", ShortWindow: "6h", BurnRate: 1, Severity: "ticket"},
+}
+
+// sloBaseWindow is the window of the SLI used to compute the SLI over the
+// SLO window.
+const sloBaseWindow = "5m"
+
+func resourcemimirSLO() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourcemimirSLOCreate,
+		ReadContext:   resourcemimirSLORead,
+		UpdateContext: resourcemimirSLOUpdate,
+		DeleteContext: resourcemimirSLODelete,
+		CustomizeDiff: resourcemimirSLOCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourcemimirSLOImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Type:        schema.TypeString,
+				Description: "Namespace of the generated rule groups.",
+				ForceNew:    true,
+				Optional:    true,
+				Default:     "default",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Description:  "SLO name, set as the `slo` label of the generated rules. The rule groups are named `<name>-sli-recordings` and `<name>-alerts`.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateGroupRuleName,
+			},
+			"good_query": {
+				Type:         schema.TypeString,
+				Description:  "PromQL query of the rate of good events, where `{{.window}}` is replaced by the window, e.g. `sum(rate(http_requests_total{code!~\"5..\"}[{{.window}}]))`.",
+				Optional:     true,
+				RequiredWith: []string{"total_query"},
+				ExactlyOneOf: []string{"good_query", "error_ratio_query"},
+				ValidateFunc: validateSLOQuery,
+			},
+			"total_query": {
+				Type:         schema.TypeString,
+				Description:  "PromQL query of the rate of total events, where `{{.window}}` is replaced by the window.",
+				Optional:     true,
+				RequiredWith: []string{"good_query"},
+				ValidateFunc: validateSLOQuery,
+			},
+			"error_ratio_query": {
+				Type:         schema.TypeString,
+				Description:  "PromQL query of the ratio of bad events, between 0 and 1, where `{{.window}}` is replaced by the window.",
+				Optional:     true,
+				ValidateFunc: validateSLOQuery,
+			},
+			"objective": {
+				Type:         schema.TypeFloat,
+				Description:  "Objective, in percent, e.g. `99.9`.",
+				Required:     true,
+				ValidateFunc: validateSLOObjective,
+			},
+			"window": {
+				Type:         schema.TypeString,
+				Description:  "Window of the SLO.",
+				Optional:     true,
+				Default:      "30d",
+				ValidateFunc: validateDuration,
+			},
+			"labels": {
+				Type:         schema.TypeMap,
+				Description:  "Labels added to all the generated rules.",
+				Optional:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				ValidateFunc: validateLabels,
+			},
+			"alert_name": {
+				Type:         schema.TypeString,
+				Description:  "Name of the generated alerts.",
+				Optional:     true,
+				Default:      "SLOErrorBudgetBurn",
+				ValidateFunc: validateAlertingRuleName,
+			},
+			"alert_labels": {
+				Type:         schema.TypeMap,
+				Description:  "Labels added to the generated alerts.",
+				Optional:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				ValidateFunc: validateLabels,
+			},
+			"alert_annotations": {
+				Type:         schema.TypeMap,
+				Description:  "Annotations added to the generated alerts.",
+				Optional:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				ValidateFunc: validateAnnotations,
+			},
+			"burn_rate_alert": {
+				Type:        schema.TypeList,
+				Description: "Multiwindow burn rate alerts. The alerts with the same severity are combined. Defaults to the alerts recommended by the Google SRE workbook.",
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"long_window": {
+							Type:         schema.TypeString,
+							Description:  "Long window over which the burn rate is computed.",
+							Required:     true,
+							ValidateFunc: validateDuration,
+						},
+						"short_window": {
+							Type:         schema.TypeString,
+							Description:  "Short window over which the burn rate is computed, so that the alert resolves quickly.",
+							Required:     true,
+							ValidateFunc: validateDuration,
+						},
+						"burn_rate": {
+							Type:        schema.TypeFloat,
+							Description: "Burn rate of the error budget firing the alert.",
+							Required:    true,
+						},
+						"severity": {
+							Type:        schema.TypeString,
+							Description: "Value of the `severity` label of the alert.",
+							Required:    true,
+						},
+					},
+				},
+			},
+			"disable_alerts": {
+				Type:        schema.TypeBool,
+				Description: "Only generate the recording rules.",
+				Optional:    true,
+				Default:     false,
+			},
+			"recording_rule": {
+				Type:        schema.TypeList,
+				Description: "Generated recording rules.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"record": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"expr": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"labels": {
+							Type:     schema.TypeMap,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Computed: true,
+						},
+					},
+				},
+			},
+			"alerting_rule": {
+				Type:        schema.TypeList,
+				Description: "Generated alerting rules.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"alert": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"expr": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"labels": {
+							Type:     schema.TypeMap,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Computed: true,
+						},
+						"annotations": {
+							Type:     schema.TypeMap,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Computed: true,
+						},
+					},
+				},
+			},
+		}, /* End schema */
+	}
+}
+
+func resourcemimirSLOCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := sloCreateUpdate(ctx, d, meta, "create"); err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%s/%s", d.Get("namespace").(string), d.Get("name").(string)))
+	return resourcemimirSLORead(ctx, d, meta)
+}
+
+func resourcemimirSLORead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*api_client)
+
+	// use id as read is also called by import
+	id_arr := strings.SplitN(d.Id(), "/", 2)
+	namespace := id_arr[0]
+	name := id_arr[1]
+
+	found := false
+	for _, key := range []string{"recording_rule", "alerting_rule"} {
+		group, err := sloReadGroup(ctx, client, namespace, sloGroupName(name, key))
+		if err != nil {
+			return diagFromErr(err)
+		}
+		if group != nil {
+			found = true
+		} else {
+			group = &rulesFileGroup{}
+		}
+
+		if err := d.Set(key, flattenSLORules(group.Rules, key == "alerting_rule")); err != nil {
+			return diagFromErr(err)
+		}
+	}
+
+	if !found {
+		d.SetId("")
+		return diag.Diagnostics{}
+	}
+
+	d.Set("namespace", namespace)
+	d.Set("name", name)
+
+	return alertPolicyWarnings(client.alert_policy, sloPolicyViolations(client, namespace, name, d.Get("alerting_rule").([]interface{})))
+}
+
+func resourcemimirSLOUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.HasChanges("recording_rule", "alerting_rule") {
+		if err := sloCreateUpdate(ctx, d, meta, "update"); err != nil {
+			return diagFromErr(err)
+		}
+	}
+	return resourcemimirSLORead(ctx, d, meta)
+}
+
+func resourcemimirSLODelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*api_client)
+	name := d.Get("name").(string)
+	namespace := d.Get("namespace").(string)
+
+	for _, key := range []string{"alerting_rule", "recording_rule"} {
+		if err := sloDeleteGroup(ctx, client, namespace, sloGroupName(name, key)); err != nil {
+			return diagFromErr(err)
+		}
+	}
+	d.SetId("")
+
+	return diag.Diagnostics{}
+}
+
+func resourcemimirSLOImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if !strings.Contains(d.Id(), "/") {
+		return nil, fmt.Errorf("Invalid SLO id %q, expected <namespace>/<name>", d.Id())
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourcemimirSLOCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	// The generated rules are computed at plan time for review.
+	for key := range resourcemimirSLO().Schema {
+		if key == "recording_rule" || key == "alerting_rule" {
+			continue
+		}
+		if !d.NewValueKnown(key) {
+			if err := d.SetNewComputed("recording_rule"); err != nil {
+				return err
+			}
+			return d.SetNewComputed("alerting_rule")
+		}
+	}
+
+	var recording, alerting *rulesFileGroup
+	var err error
+	client, ok := meta.(*api_client)
+	if ok {
+		recording, alerting, err = sloRules(client, expandSLO(d))
+	} else {
+		recording, alerting, err = generateSLORules(expandSLO(d))
+	}
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		namespace := d.Get("namespace").(string)
+		violations := checkAlertPolicy(client.alert_policy, namespace, alerting.Name, sloAlertingRules(alerting.Rules))
+		if err := alertPolicyError(client.alert_policy, violations); err != nil {
+			return err
+		}
+	}
+
+	for key, rules := range map[string][]map[string]interface{}{
+		"recording_rule": flattenSLORules(recording.Rules, false),
+		"alerting_rule":  flattenSLORules(alerting.Rules, true),
+	} {
+		if computedRulesEqual(d.Get(key).([]interface{}), rules) {
+			continue
+		}
+		if err := d.SetNew(key, rules); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func sloCreateUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}, action string) error {
+	client := meta.(*api_client)
+	namespace := d.Get("namespace").(string)
+
+	recording, alerting, err := sloRules(client, expandSLO(d))
+	if err != nil {
+		return err
+	}
+
+	if err := sloPostGroup(ctx, client, namespace, recording, action); err != nil {
+		return err
+	}
+	if len(alerting.Rules) == 0 {
+		return sloDeleteGroup(ctx, client, namespace, alerting.Name)
+	}
+	return sloPostGroup(ctx, client, namespace, alerting, action)
+}
+
+func sloPostGroup(ctx context.Context, client *api_client, namespace string, group *rulesFileGroup, action string) error {
+	data, _ := yaml.Marshal(group)
+	headers := map[string]string{"Content-Type": "application/yaml"}
+
+	path := fmt.Sprintf("/config/v1/rules/%s", namespace)
+	jobraw, err := client.send_request(ctx, "ruler", "POST", path, string(data), headers)
+	baseMsg := fmt.Sprintf("Cannot %s SLO rule group '%s' -", action, group.Name)
+	fullurl := fmt.Sprintf("%s%s", client.uri, path)
+	if err := handleHTTPError(err, jobraw, fullurl, baseMsg); err != nil {
+		return err
+	}
+	client.rule_groups.set(namespace, string(data))
+
+	return nil
+}
+
+func sloReadGroup(ctx context.Context, client *api_client, namespace, name string) (*rulesFileGroup, error) {
+	var headers map[string]string
+	path := fmt.Sprintf("/config/v1/rules/%s/%s", namespace, name)
+	jobraw, err := client.send_request(ctx, "ruler", "GET", path, "", headers)
+
+	baseMsg := fmt.Sprintf("Cannot read SLO rule group '%s' -", name)
+	fullurl := fmt.Sprintf("%s%s", client.uri, path)
+	err = handleHTTPError(err, jobraw, fullurl, baseMsg)
+	if err != nil {
+		if isNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var group rulesFileGroup
+	if err := yaml.Unmarshal([]byte(jobraw), &group); err != nil {
+		return nil, fmt.Errorf("Unable to decode SLO rule group '%s' data: %v", name, err)
+	}
+
+	return &group, nil
+}
+
+func sloDeleteGroup(ctx context.Context, client *api_client, namespace, name string) error {
+	var headers map[string]string
+	path := fmt.Sprintf("/config/v1/rules/%s/%s", namespace, name)
+	_, err := client.send_request(ctx, "ruler", "DELETE", path, "", headers)
+	if err != nil && !isNotFoundError(err) {
+		return fmt.Errorf(
+			"Cannot delete SLO rule group '%s' from %s: %w",
+			name,
+			fmt.Sprintf("%s%s", client.uri, path),
+			err)
+	}
+	client.rule_groups.delete(namespace, name)
+
+	return nil
+}
+
+func sloGroupName(name, key string) string {
+	if key == "alerting_rule" {
+		return fmt.Sprintf("%s-alerts", name)
+	}
+	return fmt.Sprintf("%s-sli-recordings", name)
+}
+
+// sloRules returns the generated rule groups, with the enforced matchers of
+// the provider set on the SLO queries and the default labels and annotations
+// merged into the alerts like for the alerting rule groups.
+func sloRules(client *api_client, s slo) (*rulesFileGroup, *rulesFileGroup, error) {
+	s.EnforcedMatchers = client.enforced_matchers
+	recording, alerting, err := generateSLORules(s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rules := sloAlertingRules(alerting.Rules)
+	defaults := alertingRuleDefaults{
+		Namespace:   s.Namespace,
+		Group:       alerting.Name,
+		Labels:      client.default_alert_labels,
+		Annotations: client.default_alert_annotations,
+	}
+	if err := applyAlertingRuleDefaults(rules, defaults); err != nil {
+		return nil, nil, err
+	}
+	for i := range alerting.Rules {
+		alerting.Rules[i].Labels = rules[i].Labels
+		alerting.Rules[i].Annotations = rules[i].Annotations
+	}
+
+	return recording, alerting, nil
+}
+
+// sloPolicyViolations checks the alert policy on the generated alerts of the
+// state.
+func sloPolicyViolations(client *api_client, namespace, name string, v []interface{}) []string {
+	var rules []alertingRule
+	for _, raw := range v {
+		data := raw.(map[string]interface{})
+		rules = append(rules, alertingRule{
+			Alert:       data["alert"].(string),
+			Labels:      expandStringMap(data["labels"].(map[string]interface{})),
+			Annotations: expandStringMap(data["annotations"].(map[string]interface{})),
+		})
+	}
+
+	return checkAlertPolicy(client.alert_policy, namespace, sloGroupName(name, "alerting_rule"), rules)
+}
+
+func sloAlertingRules(v []rulesFileRule) []alertingRule {
+	var rules []alertingRule
+	for _, r := range v {
+		rules = append(rules, alertingRule{
+			Alert:       r.Alert,
+			Expr:        r.Expr,
+			For:         r.For,
+			Labels:      r.Labels,
+			Annotations: r.Annotations,
+		})
+	}
+	return rules
+}
+
+type sloBurnRateAlert struct {
+	LongWindow  string
+	ShortWindow string
+	BurnRate    float64
+	Severity    string
+}
+
+type slo struct {
+	Namespace        string
+	Name             string
+	GoodQuery        string
+	TotalQuery       string
+	ErrorRatioQuery  string
+	Objective        float64
+	Window           string
+	Labels           map[string]string
+	AlertName        string
+	AlertLabels      map[string]string
+	AlertAnnotations map[string]string
+	BurnRateAlerts   []sloBurnRateAlert
+	DisableAlerts    bool
+	// EnforcedMatchers are set on the selectors of the SLO queries only, as
+	// the recorded series of the SLO no longer have the enforced labels.
+	EnforcedMatchers map[string]string
+}
+
+func expandSLO(d resourceDataGetter) slo {
+	s := slo{
+		Namespace:        d.Get("namespace").(string),
+		Name:             d.Get("name").(string),
+		GoodQuery:        d.Get("good_query").(string),
+		TotalQuery:       d.Get("total_query").(string),
+		ErrorRatioQuery:  d.Get("error_ratio_query").(string),
+		Objective:        d.Get("objective").(float64),
+		Window:           d.Get("window").(string),
+		Labels:           expandStringMap(d.Get("labels").(map[string]interface{})),
+		AlertName:        d.Get("alert_name").(string),
+		AlertLabels:      expandStringMap(d.Get("alert_labels").(map[string]interface{})),
+		AlertAnnotations: expandStringMap(d.Get("alert_annotations").(map[string]interface{})),
+		BurnRateAlerts:   sloDefaultBurnRateAlerts,
+		DisableAlerts:    d.Get("disable_alerts").(bool),
+	}
+
+	if v := d.Get("burn_rate_alert").([]interface{}); len(v) > 0 {
+		s.BurnRateAlerts = nil
+		for _, raw := range v {
+			data := raw.(map[string]interface{})
+			s.BurnRateAlerts = append(s.BurnRateAlerts, sloBurnRateAlert{
+				LongWindow:  data["long_window"].(string),
+				ShortWindow: data["short_window"].(string),
+				BurnRate:    data["burn_rate"].(float64),
+				Severity:    data["severity"].(string),
+			})
+		}
+	}
+
+	return s
+}
+
+// generateSLORules returns the SLI recording rules, for every window of the
+// alerts and the SLO window, and the multiwindow, multi-burn-rate alerts.
+func generateSLORules(s slo) (*rulesFileGroup, *rulesFileGroup, error) {
+	recording := &rulesFileGroup{Name: sloGroupName(s.Name, "recording_rule")}
+	alerting := &rulesFileGroup{Name: sloGroupName(s.Name, "alerting_rule")}
+
+	// The namespace is part of the labels, so that the recorded series of
+	// the SLOs with the same name in other namespaces are not selected.
+	labels := map[string]string{"slo": s.Name, "slo_namespace": s.Namespace}
+	for key, val := range s.Labels {
+		labels[key] = val
+	}
+	selector := fmt.Sprintf(`{slo=%q,slo_namespace=%q}`, s.Name, s.Namespace)
+	errorBudget := formatSLOFloat((100 - s.Objective) / 100)
+
+	// The SLI over the SLO window is the average of the base window SLI,
+	// which is cheaper than a rate over the whole window.
+	window := normalizeRulesSyncDuration(s.Window)
+	windows := map[string]bool{sloBaseWindow: true}
+	for _, alert := range s.BurnRateAlerts {
+		windows[normalizeRulesSyncDuration(alert.LongWindow)] = true
+		windows[normalizeRulesSyncDuration(alert.ShortWindow)] = true
+	}
+	delete(windows, window)
+
+	for _, w := range sortSLOWindows(windows) {
+		expr, err := sloErrorRatioExpr(s, w)
+		if err != nil {
+			return nil, nil, err
+		}
+		recording.Rules = append(recording.Rules, rulesFileRule{
+			Record: sloRecordName(w),
+			Expr:   expr,
+			Labels: labels,
+		})
+	}
+	recording.Rules = append(recording.Rules,
+		rulesFileRule{
+			Record: sloRecordName(window),
+			Expr: fmt.Sprintf("sum_over_time(%s%s[%s]) / count_over_time(%s%s[%s])",
+				sloRecordName(sloBaseWindow), selector, window, sloRecordName(sloBaseWindow), selector, window),
+			Labels: labels,
+		},
+		rulesFileRule{
+			Record: "slo:objective:ratio",
+			Expr:   fmt.Sprintf("vector(%s)", formatSLOFloat(s.Objective/100)),
+			Labels: labels,
+		},
+		rulesFileRule{
+			Record: "slo:error_budget:ratio",
+			Expr:   fmt.Sprintf("vector(%s)", errorBudget),
+			Labels: labels,
+		},
+		rulesFileRule{
+			Record: "slo:period_error_budget_remaining:ratio",
+			Expr:   fmt.Sprintf("1 - %s%s / %s", sloRecordName(window), selector, errorBudget),
+			Labels: labels,
+		},
+	)
+
+	if s.DisableAlerts {
+		return recording, alerting, nil
+	}
+
+	var severities []string
+	conditions := make(map[string][]string)
+	for _, alert := range s.BurnRateAlerts {
+		if _, ok := conditions[alert.Severity]; !ok {
+			severities = append(severities, alert.Severity)
+		}
+		threshold := formatSLOFloat(alert.BurnRate * (100 - s.Objective) / 100)
+		conditions[alert.Severity] = append(conditions[alert.Severity], fmt.Sprintf("(%s%s > %s and %s%s > %s)",
+			sloRecordName(normalizeRulesSyncDuration(alert.LongWindow)), selector, threshold,
+			sloRecordName(normalizeRulesSyncDuration(alert.ShortWindow)), selector, threshold))
+	}
+
+	for _, severity := range severities {
+		alertLabels := make(map[string]string)
+		for key, val := range labels {
+			alertLabels[key] = val
+		}
+		for key, val := range s.AlertLabels {
+			alertLabels[key] = val
+		}
+		alertLabels["severity"] = severity
+
+		var annotations map[string]string
+		if len(s.AlertAnnotations) > 0 {
+			annotations = s.AlertAnnotations
+		}
+
+		alerting.Rules = append(alerting.Rules, rulesFileRule{
+			Alert:       s.AlertName,
+			Expr:        strings.Join(conditions[severity], " or "),
+			Labels:      alertLabels,
+			Annotations: annotations,
+		})
+	}
+
+	return recording, alerting, nil
+}
+
+func sloErrorRatioExpr(s slo, window string) (string, error) {
+	if s.ErrorRatioQuery != "" {
+		return sloQuery(s, s.ErrorRatioQuery, window)
+	}
+
+	good, err := sloQuery(s, s.GoodQuery, window)
+	if err != nil {
+		return "", err
+	}
+	total, err := sloQuery(s, s.TotalQuery, window)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("1 - ((%s) / (%s))", good, total), nil
+}
+
+// sloQuery renders a query of the SLO for the window, with the enforced
+// matchers set on its selectors.
+func sloQuery(s slo, query, window string) (string, error) {
+	expr, err := renderSLOQuery(query, window)
+	if err != nil {
+		return "", err
+	}
+	return enforceMatchers(expr, s.EnforcedMatchers)
+}
+
+func renderSLOQuery(query, window string) (string, error) {
+	tmpl, err := template.New("").Option("missingkey=error").Parse(query)
+	if err != nil {
+		return "", fmt.Errorf("Invalid SLO query %q: %v", query, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, map[string]string{"window": window}); err != nil {
+		return "", fmt.Errorf("Cannot render SLO query %q: %v", query, err)
+	}
+
+	return buf.String(), nil
+}
+
+func sloRecordName(window string) string {
+	return fmt.Sprintf("slo:sli_error:ratio_rate%s", window)
+}
+
+func sortSLOWindows(windows map[string]bool) []string {
+	var sorted []string
+	for w := range windows {
+		sorted = append(sorted, w)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, _ := model.ParseDuration(sorted[i])
+		b, _ := model.ParseDuration(sorted[j])
+		return a < b
+	})
+
+	return sorted
+}
+
+// formatSLOFloat formats ratios without the floating point noise.
+func formatSLOFloat(v float64) string {
+	r, _ := strconv.ParseFloat(strconv.FormatFloat(v, 'g', 10, 64), 64)
+	return strconv.FormatFloat(r, 'f', -1, 64)
+}
+
+func flattenSLORules(v []rulesFileRule, alerting bool) []map[string]interface{} {
+	var rules []map[string]interface{}
+
+	for _, v := range v {
+		rule := make(map[string]interface{})
+		if alerting {
+			rule["alert"] = v.Alert
+			if v.Annotations != nil {
+				rule["annotations"] = v.Annotations
+			}
+		} else {
+			rule["record"] = v.Record
+		}
+		rule["expr"] = v.Expr
+		if v.Labels != nil {
+			rule["labels"] = v.Labels
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+func validateSLOQuery(v interface{}, k string) (ws []string, errors []error) {
+	expr, err := renderSLOQuery(v.(string), sloBaseWindow)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("\"%s\": %v", k, err))
+		return
+	}
+
+	return validatePromQLExpr(expr, k)
+}
+
+func validateSLOObjective(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(float64)
+
+	if value <= 0 || value >= 100 {
+		errors = append(errors, fmt.Errorf("\"%s\": objective must be between 0 and 100 excluded, got %v", k, value))
+	}
+
+	return
+}
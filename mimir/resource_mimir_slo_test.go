@@ -0,0 +1,244 @@
+package mimir
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+func TestAccResourceSLO_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMimirSLODestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceSLO_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mimir_slo.api", "id", "namespace_1/api"),
+					resource.TestCheckResourceAttr("mimir_slo.api", "recording_rule.#", "11"),
+					resource.TestCheckResourceAttr("mimir_slo.api", "recording_rule.0.record", "slo:sli_error:ratio_rate5m"),
+					resource.TestCheckResourceAttr("mimir_slo.api", "recording_rule.0.expr", `1 - ((sum(rate(http_requests_total{code!~"5.."}[5m]))) / (sum(rate(http_requests_total[5m]))))`),
+					resource.TestCheckResourceAttr("mimir_slo.api", "recording_rule.0.labels.slo", "api"),
+					resource.TestCheckResourceAttr("mimir_slo.api", "recording_rule.0.labels.slo_namespace", "namespace_1"),
+					resource.TestCheckResourceAttr("mimir_slo.api", "recording_rule.8.expr", "vector(0.999)"),
+					resource.TestCheckResourceAttr("mimir_slo.api", "alerting_rule.#", "2"),
+					resource.TestCheckResourceAttr("mimir_slo.api", "alerting_rule.0.labels.severity", "page"),
+					resource.TestCheckResourceAttr("mimir_slo.api", "alerting_rule.0.expr", `(slo:sli_error:ratio_rate1h{slo="api",slo_namespace="namespace_1"} > 0.0144 and slo:sli_error:ratio_rate5m{slo="api",slo_namespace="namespace_1"} > 0.0144) or (slo:sli_error:ratio_rate6h{slo="api",slo_namespace="namespace_1"} > 0.006 and slo:sli_error:ratio_rate30m{slo="api",slo_namespace="namespace_1"} > 0.006)`),
+					resource.TestCheckResourceAttr("mimir_slo.api", "alerting_rule.1.labels.severity", "ticket"),
+				),
+			},
+			{
+				Config: testAccResourceSLO_errorRatio,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mimir_slo.api", "recording_rule.#", "8"),
+					resource.TestCheckResourceAttr("mimir_slo.api", "recording_rule.0.expr", `sum(rate(http_requests_total{code=~"5.."}[5m])) / sum(rate(http_requests_total[5m]))`),
+					resource.TestCheckResourceAttr("mimir_slo.api", "recording_rule.5.expr", "vector(0.99)"),
+					resource.TestCheckResourceAttr("mimir_slo.api", "alerting_rule.#", "1"),
+					resource.TestCheckResourceAttr("mimir_slo.api", "alerting_rule.0.alert", "APIErrorBudgetBurn"),
+					resource.TestCheckResourceAttr("mimir_slo.api", "alerting_rule.0.annotations.summary", "API error budget burn"),
+				),
+			},
+			{
+				Config: testAccResourceSLO_disableAlerts,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mimir_slo.api", "alerting_rule.#", "0"),
+				),
+			},
+			{
+				ResourceName:      "mimir_slo.api",
+				ImportState:       true,
+				ImportStateId:     "namespace_1/api",
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"error_ratio_query", "objective", "disable_alerts", "window", "alert_name",
+				},
+			},
+		},
+	})
+}
+
+func TestSLORules(t *testing.T) {
+	client := &api_client{
+		default_alert_labels:      map[string]string{"team": "sre", "severity": "none"},
+		default_alert_annotations: map[string]string{"runbook": "https://runbooks/[[ .Namespace ]]/[[ .Group ]]"},
+	}
+	s := slo{
+		Namespace:       "namespace_1",
+		Name:            "api",
+		ErrorRatioQuery: "sum(rate(errors[{{.window}}]))",
+		Objective:       99,
+		Window:          "30d",
+		AlertName:       "SLOErrorBudgetBurn",
+		BurnRateAlerts:  sloDefaultBurnRateAlerts[:1],
+	}
+
+	recording, alerting, err := sloRules(client, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := recording.Rules[0].Labels["slo_namespace"]; got != "namespace_1" {
+		t.Errorf("Got slo_namespace label %q but expected %q", got, "namespace_1")
+	}
+	if !strings.Contains(alerting.Rules[0].Expr, `{slo="api",slo_namespace="namespace_1"}`) {
+		t.Errorf("Got alert expression %q without the namespace selector", alerting.Rules[0].Expr)
+	}
+
+	// The generated severity wins over the default one.
+	expectedLabels := map[string]string{"slo": "api", "slo_namespace": "namespace_1", "team": "sre", "severity": "page"}
+	if got := alerting.Rules[0].Labels; !reflect.DeepEqual(got, expectedLabels) {
+		t.Errorf("Got alert labels %v but expected %v", got, expectedLabels)
+	}
+	if got := alerting.Rules[0].Annotations["runbook"]; got != "https://runbooks/namespace_1/api-alerts" {
+		t.Errorf("Got runbook annotation %q", got)
+	}
+
+	client.alert_policy = &alertPolicy{RequiredAnnotations: []string{"summary"}}
+	if violations := checkAlertPolicy(client.alert_policy, s.Namespace, alerting.Name, sloAlertingRules(alerting.Rules)); len(violations) != 1 {
+		t.Errorf("Got violations %v but expected one for the missing summary", violations)
+	}
+
+	// The enforced matchers are set on the SLO query only, not on the
+	// recorded series of the SLO.
+	client.enforced_matchers = map[string]string{"cluster": "eu-1"}
+	recording, alerting, err = sloRules(client, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rule := range append(recording.Rules, alerting.Rules...) {
+		query := strings.HasPrefix(rule.Expr, "sum(rate(errors")
+		if enforced := strings.Contains(rule.Expr, `cluster="eu-1"`); enforced != query {
+			t.Errorf("Got expression %q, expected the enforced matchers on the SLO query only", rule.Expr)
+		}
+	}
+	node, err := parser.ParseExpr(alerting.Rules[0].Expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser.Inspect(node, func(n parser.Node, _ []parser.Node) error {
+		if vs, ok := n.(*parser.VectorSelector); ok {
+			var names []string
+			for _, m := range vs.LabelMatchers {
+				if m.Name != "__name__" {
+					names = append(names, m.Name)
+				}
+			}
+			if !reflect.DeepEqual(names, []string{"slo", "slo_namespace"}) {
+				t.Errorf("Got matchers %v on %s but expected only slo and slo_namespace", names, vs)
+			}
+		}
+		return nil
+	})
+}
+
+func TestAccResourceSLO_expectValidationError(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAccResourceSLO_objective, 100),
+				ExpectError: regexp.MustCompile("objective must be between 0 and 100"),
+			},
+			{
+				Config:      testAccResourceSLO_expectPromQLValidationError,
+				ExpectError: regexp.MustCompile("Invalid PromQL expression"),
+			},
+		},
+	})
+}
+
+func testAccCheckMimirSLODestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*api_client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "mimir_slo" {
+			continue
+		}
+
+		for _, key := range []string{"recording_rule", "alerting_rule"} {
+			name := sloGroupName(rs.Primary.Attributes["name"], key)
+			var headers map[string]string
+			path := fmt.Sprintf("/config/v1/rules/%s/%s", rs.Primary.Attributes["namespace"], name)
+			_, err := client.send_request(context.Background(), "ruler", "GET", path, "", headers)
+			if err == nil {
+				return fmt.Errorf("rule group '%s' still exists", name)
+			}
+			if !isNotFoundError(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+const testAccResourceSLO_basic = `
+	resource "mimir_slo" "api" {
+		name        = "api"
+		namespace   = "namespace_1"
+		good_query  = "sum(rate(http_requests_total{code!~\"5..\"}[{{.window}}]))"
+		total_query = "sum(rate(http_requests_total[{{.window}}]))"
+		objective   = 99.9
+	}
+`
+
+const testAccResourceSLO_errorRatio = `
+	resource "mimir_slo" "api" {
+		name              = "api"
+		namespace         = "namespace_1"
+		error_ratio_query = "sum(rate(http_requests_total{code=~\"5..\"}[{{.window}}])) / sum(rate(http_requests_total[{{.window}}]))"
+		objective         = 99
+		window            = "7d"
+		alert_name        = "APIErrorBudgetBurn"
+		alert_annotations = {
+			summary = "API error budget burn"
+		}
+		burn_rate_alert {
+			long_window  = "1h"
+			short_window = "5m"
+			burn_rate    = 14.4
+			severity     = "critical"
+		}
+		burn_rate_alert {
+			long_window  = "6h"
+			short_window = "30m"
+			burn_rate    = 6
+			severity     = "critical"
+		}
+	}
+`
+
+const testAccResourceSLO_disableAlerts = `
+	resource "mimir_slo" "api" {
+		name              = "api"
+		namespace         = "namespace_1"
+		error_ratio_query = "sum(rate(http_requests_total{code=~\"5..\"}[{{.window}}])) / sum(rate(http_requests_total[{{.window}}]))"
+		objective         = 99
+		disable_alerts    = true
+	}
+`
+
+const testAccResourceSLO_objective = `
+	resource "mimir_slo" "api" {
+		name              = "api"
+		error_ratio_query = "sum(rate(http_errors_total[{{.window}}]))"
+		objective         = %d
+	}
+`
+
+const testAccResourceSLO_expectPromQLValidationError = `
+	resource "mimir_slo" "api" {
+		name              = "api"
+		error_ratio_query = "sum(rate(http_errors_total[{{.window}}])"
+		objective         = 99.9
+	}
+`
@@ -0,0 +1,421 @@
+package mimir
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+const (
+	ruleDependencyUndefined = "undefined"
+	ruleDependencyOrder     = "order"
+	ruleDependencyCycle     = "cycle"
+)
+
+// ruleDependencyNode is a rule of the tenant and the recorded metrics it uses.
+type ruleDependencyNode struct {
+	Namespace string
+	Group     string
+	Index     int
+	Name      string
+	Record    bool
+	DependsOn []string
+}
+
+type ruleDependencyProblem struct {
+	Type      string
+	Namespace string
+	Group     string
+	Rule      string
+	Message   string
+}
+
+type ruleDependencyGraph struct {
+	Nodes []ruleDependencyNode
+	// records are the nodes recording each metric
+	records map[string][]int
+}
+
+// isRecordedMetric reports whether a metric name follows the level:metric:operations
+// naming convention of the recording rules, i.e. has three non-empty parts.
+// Other names with colons, e.g. of federated or exported metrics, are not
+// expected to be recorded by the tenant.
+func isRecordedMetric(name string) bool {
+	parts := strings.Split(name, ":")
+	if len(parts) != 3 {
+		return false
+	}
+	for _, part := range parts {
+		if part == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// buildRuleDependencyGraph builds the graph of the rule groups by namespace.
+// A rule depends on the metrics of its expression which are either recorded by
+// a rule or named like recorded metrics.
+func buildRuleDependencyGraph(groups map[string][]rulesFileGroup) (*ruleDependencyGraph, error) {
+	g := &ruleDependencyGraph{records: make(map[string][]int)}
+
+	namespaces := make([]string, 0, len(groups))
+	for namespace := range groups {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	metrics := make(map[int][]string)
+	for _, namespace := range namespaces {
+		for _, group := range groups[namespace] {
+			for i, rule := range group.Rules {
+				names, err := exprMetricNames(rule.Expr)
+				if err != nil {
+					return nil, fmt.Errorf("rule group '%s/%s': %v", namespace, group.Name, err)
+				}
+
+				node := ruleDependencyNode{
+					Namespace: namespace,
+					Group:     group.Name,
+					Index:     i,
+					Name:      rule.Alert,
+				}
+				if rule.Record != "" {
+					node.Name = rule.Record
+					node.Record = true
+					g.records[rule.Record] = append(g.records[rule.Record], len(g.Nodes))
+				}
+				metrics[len(g.Nodes)] = names
+				g.Nodes = append(g.Nodes, node)
+			}
+		}
+	}
+
+	for i := range g.Nodes {
+		for _, name := range metrics[i] {
+			if _, ok := g.records[name]; ok || isRecordedMetric(name) {
+				g.Nodes[i].DependsOn = append(g.Nodes[i].DependsOn, name)
+			}
+		}
+	}
+
+	return g, nil
+}
+
+// exprMetricNames returns the sorted metric names selected by an expression.
+func exprMetricNames(expr string) ([]string, error) {
+	node, err := parser.ParseExpr(expr)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid PromQL expression %q: %v", expr, err)
+	}
+
+	seen := make(map[string]bool)
+	parser.Inspect(node, func(n parser.Node, _ []parser.Node) error {
+		vs, ok := n.(*parser.VectorSelector)
+		if !ok {
+			return nil
+		}
+		name := vs.Name
+		for _, m := range vs.LabelMatchers {
+			if m.Name == labels.MetricName && m.Type == labels.MatchEqual {
+				name = m.Value
+			}
+		}
+		if name != "" {
+			seen[name] = true
+		}
+		return nil
+	})
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// Problems returns the uses of undefined recorded metrics, the uses of metrics
+// recorded later in the same group, which lag by one evaluation, and the
+// cycles between recorded metrics.
+func (g *ruleDependencyGraph) Problems() []ruleDependencyProblem {
+	var problems []ruleDependencyProblem
+
+	for _, node := range g.Nodes {
+		prefix := fmt.Sprintf("rule %q of rule group '%s/%s'", node.Name, node.Namespace, node.Group)
+		for _, name := range node.DependsOn {
+			definers, ok := g.records[name]
+			if !ok {
+				problems = append(problems, ruleDependencyProblem{
+					Type:      ruleDependencyUndefined,
+					Namespace: node.Namespace,
+					Group:     node.Group,
+					Rule:      node.Name,
+					Message:   fmt.Sprintf("%s uses the undefined recorded metric %q", prefix, name),
+				})
+				continue
+			}
+			for _, i := range definers {
+				definer := g.Nodes[i]
+				if definer.Namespace == node.Namespace && definer.Group == node.Group && definer.Index > node.Index {
+					problems = append(problems, ruleDependencyProblem{
+						Type:      ruleDependencyOrder,
+						Namespace: node.Namespace,
+						Group:     node.Group,
+						Rule:      node.Name,
+						Message:   fmt.Sprintf("%s uses %q recorded later in the same group", prefix, name),
+					})
+					break
+				}
+			}
+		}
+	}
+
+	for _, cycle := range g.cycles() {
+		quoted := make([]string, 0, len(cycle))
+		for _, name := range cycle {
+			quoted = append(quoted, fmt.Sprintf("%q", name))
+		}
+		for _, name := range cycle {
+			for _, i := range g.records[name] {
+				node := g.Nodes[i]
+				msg := fmt.Sprintf("rule %q of rule group '%s/%s' is part of the dependency cycle between %s",
+					node.Name, node.Namespace, node.Group, strings.Join(quoted, ", "))
+				if len(cycle) == 1 {
+					msg = fmt.Sprintf("rule %q of rule group '%s/%s' depends on itself", node.Name, node.Namespace, node.Group)
+				}
+				problems = append(problems, ruleDependencyProblem{
+					Type:      ruleDependencyCycle,
+					Namespace: node.Namespace,
+					Group:     node.Group,
+					Rule:      node.Name,
+					Message:   msg,
+				})
+			}
+		}
+	}
+
+	return problems
+}
+
+// cycles returns the sorted recorded metrics of each cycle, found as the
+// strongly connected components of the graph with Tarjan's algorithm.
+func (g *ruleDependencyGraph) cycles() [][]string {
+	names := make([]string, 0, len(g.records))
+	edges := make(map[string][]string)
+	for name, definers := range g.records {
+		names = append(names, name)
+		for _, i := range definers {
+			for _, dep := range g.Nodes[i].DependsOn {
+				if _, ok := g.records[dep]; ok {
+					edges[name] = append(edges[name], dep)
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+
+	var cycles [][]string
+	index := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+
+	var connect func(name string)
+	connect = func(name string) {
+		index[name] = len(index)
+		lowlink[name] = index[name]
+		stack = append(stack, name)
+		onStack[name] = true
+
+		selfLoop := false
+		for _, dep := range edges[name] {
+			if dep == name {
+				selfLoop = true
+			}
+			if _, ok := index[dep]; !ok {
+				connect(dep)
+				if lowlink[dep] < lowlink[name] {
+					lowlink[name] = lowlink[dep]
+				}
+			} else if onStack[dep] && index[dep] < lowlink[name] {
+				lowlink[name] = index[dep]
+			}
+		}
+
+		if lowlink[name] != index[name] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == name {
+				break
+			}
+		}
+		if len(component) > 1 || selfLoop {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, name := range names {
+		if _, ok := index[name]; !ok {
+			connect(name)
+		}
+	}
+
+	return cycles
+}
+
+// ruleGroupDependencyViolations checks the dependencies of a rule group of the
+// tenant once replaced by the given rules. The undefined recorded metrics are
+// only checked when undefined is set.
+func ruleGroupDependencyViolations(ctx context.Context, client *api_client, namespace, name string, rules []rulesFileRule, undefined bool) ([]string, error) {
+	var violations []string
+
+	if client.rule_dependencies_check == "" {
+		return violations, nil
+	}
+
+	groups, err := ruleGroupsWith(ctx, client, namespace, name, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := buildRuleDependencyGraph(groups)
+	if err != nil {
+		return nil, err
+	}
+	for _, problem := range graph.Problems() {
+		if problem.Namespace != namespace || problem.Group != name {
+			continue
+		}
+		if problem.Type == ruleDependencyUndefined && !undefined {
+			continue
+		}
+		violations = append(violations, problem.Message)
+	}
+
+	return violations, nil
+}
+
+// ruleDependenciesCustomizeDiff checks the order and the cycle problems of a
+// rule group at plan time. The undefined recorded metrics are checked at apply
+// time, once the rule groups the group depends on have been created.
+func ruleDependenciesCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, client *api_client) error {
+	if client.rule_dependencies_check != alertPolicyLevelError {
+		return nil
+	}
+	for _, key := range []string{"namespace", "name", "rule"} {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+	}
+	for i := range d.Get("rule").([]interface{}) {
+		if !d.NewValueKnown(fmt.Sprintf("rule.%d.expr", i)) {
+			return nil
+		}
+	}
+
+	violations, err := ruleGroupDependencyViolations(ctx, client, d.Get("namespace").(string), d.Get("name").(string), expandRuleGroupDependencyRules(d), false)
+	if err != nil {
+		return err
+	}
+	return ruleDependenciesError(client.rule_dependencies_check, violations)
+}
+
+// checkRuleGroupDependencies checks all the problems of a rule group before it
+// is sent to the ruler.
+func checkRuleGroupDependencies(ctx context.Context, d resourceDataGetter, client *api_client) error {
+	if client.rule_dependencies_check != alertPolicyLevelError {
+		return nil
+	}
+
+	violations, err := ruleGroupDependencyViolations(ctx, client, d.Get("namespace").(string), d.Get("name").(string), expandRuleGroupDependencyRules(d), true)
+	if err != nil {
+		return err
+	}
+	return ruleDependenciesError(client.rule_dependencies_check, violations)
+}
+
+// ruleGroupDependencyWarnings returns the problems of a rule group as warnings
+// when the check level is warning.
+func ruleGroupDependencyWarnings(ctx context.Context, d resourceDataGetter, client *api_client) diag.Diagnostics {
+	if client.rule_dependencies_check != alertPolicyLevelWarning {
+		return diag.Diagnostics{}
+	}
+
+	violations, err := ruleGroupDependencyViolations(ctx, client, d.Get("namespace").(string), d.Get("name").(string), expandRuleGroupDependencyRules(d), true)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	return ruleDependenciesWarnings(client.rule_dependencies_check, violations)
+}
+
+// expandRuleGroupDependencyRules returns the names and expressions of the rules
+// of an alerting or recording rule group.
+func expandRuleGroupDependencyRules(d resourceDataGetter) []rulesFileRule {
+	var rules []rulesFileRule
+
+	for _, raw := range d.Get("rule").([]interface{}) {
+		data := raw.(map[string]interface{})
+		var rule rulesFileRule
+		if v, ok := data["record"].(string); ok {
+			rule.Record = v
+		}
+		if v, ok := data["alert"].(string); ok {
+			rule.Alert = v
+		}
+		rule.Expr, _ = data["expr"].(string)
+		if v, ok := data["labels"].(map[string]interface{}); ok && len(v) > 0 {
+			rule.Labels = expandStringMap(v)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// ruleDependenciesError returns the error failing the plan or the apply when
+// the check level is error.
+func ruleDependenciesError(level string, violations []string) error {
+	if level != alertPolicyLevelError || len(violations) == 0 {
+		return nil
+	}
+
+	msg := "Rule dependency problems:"
+	for _, v := range violations {
+		msg += "\n  - " + v
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+// ruleDependenciesWarnings returns the warnings of the problems when the check
+// level is warning.
+func ruleDependenciesWarnings(level string, violations []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if level != alertPolicyLevelWarning {
+		return diags
+	}
+
+	for _, v := range violations {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Rule dependency problem",
+			Detail:   v,
+		})
+	}
+	return diags
+}
@@ -0,0 +1,54 @@
+package mimir
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsRecordedMetric(t *testing.T) {
+	tests := map[string]bool{
+		"up":                        false,
+		"job:up:sum":                true,
+		"instance:node_cpu:rate5m":  true,
+		"node:cpu":                  false,
+		":up:sum":                   false,
+		"job::sum":                  false,
+		"job:up:sum:extra":          false,
+		"cluster:job:up:rate5m:sum": false,
+	}
+
+	for name, expected := range tests {
+		if got := isRecordedMetric(name); got != expected {
+			t.Errorf("isRecordedMetric(%q) = %v but expected %v", name, got, expected)
+		}
+	}
+}
+
+func TestRuleDependencyGraphProblems(t *testing.T) {
+	groups := map[string][]rulesFileGroup{
+		"ns": {
+			{
+				Name: "a",
+				Rules: []rulesFileRule{
+					{Alert: "Uses", Expr: "job:up:sum == 0 or node:cpu > 1 or job:missing:sum"},
+					{Record: "job:up:sum", Expr: "sum by (job) (up)"},
+				},
+			},
+		},
+	}
+
+	graph, err := buildRuleDependencyGraph(groups)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var types []string
+	for _, problem := range graph.Problems() {
+		types = append(types, problem.Type)
+	}
+	// node:cpu does not follow the convention and is not reported.
+	expected := []string{ruleDependencyUndefined, ruleDependencyOrder}
+	if !reflect.DeepEqual(types, expected) {
+		t.Fatalf("Got problems %v but expected %v", types, expected)
+	}
+}
@@ -0,0 +1,246 @@
+package mimir
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+const (
+	ruleDuplicateRecord = "record"
+	ruleDuplicateAlert  = "alert"
+)
+
+// ruleDuplicate is a recorded series or an alert defined by several rule
+// groups.
+type ruleDuplicate struct {
+	Type   string
+	Name   string
+	Labels map[string]string
+	// Owners are the rule groups defining the rule, as namespace/name.
+	Owners []string
+}
+
+func (r ruleDuplicate) Message() string {
+	quoted := make([]string, 0, len(r.Owners))
+	for _, owner := range r.Owners {
+		quoted = append(quoted, fmt.Sprintf("'%s'", owner))
+	}
+	owners := strings.Join(quoted[:len(quoted)-1], ", ") + " and " + quoted[len(quoted)-1]
+
+	if r.Type == ruleDuplicateRecord {
+		series := r.Name
+		if len(r.Labels) > 0 {
+			series += labels.FromMap(r.Labels).String()
+		}
+		return fmt.Sprintf("recorded series %q is defined by the rule groups %s", series, owners)
+	}
+	if len(r.Labels) == 0 {
+		return fmt.Sprintf("alert %q is defined by the rule groups %s", r.Name, owners)
+	}
+	return fmt.Sprintf("alert %q with labels %s is defined by the rule groups %s", r.Name, labels.FromMap(r.Labels).String(), owners)
+}
+
+// findRuleDuplicates returns the recorded series and the alerts, identified by
+// their name and labels, defined by more than one rule group.
+func findRuleDuplicates(groups map[string][]rulesFileGroup) []ruleDuplicate {
+	owners := make(map[string]map[string]bool)
+	rules := make(map[string]ruleDuplicate)
+
+	for namespace, v := range groups {
+		for _, group := range v {
+			for _, rule := range group.Rules {
+				r := ruleDuplicate{Type: ruleDuplicateAlert, Name: rule.Alert, Labels: rule.Labels}
+				if rule.Record != "" {
+					r.Type = ruleDuplicateRecord
+					r.Name = rule.Record
+				}
+				key := r.Type + "/" + r.Name + labels.FromMap(r.Labels).String()
+				if owners[key] == nil {
+					owners[key] = make(map[string]bool)
+					rules[key] = r
+				}
+				owners[key][fmt.Sprintf("%s/%s", namespace, group.Name)] = true
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(rules))
+	for key := range rules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var duplicates []ruleDuplicate
+	for _, key := range keys {
+		if len(owners[key]) < 2 {
+			continue
+		}
+		r := rules[key]
+		for owner := range owners[key] {
+			r.Owners = append(r.Owners, owner)
+		}
+		sort.Strings(r.Owners)
+		duplicates = append(duplicates, r)
+	}
+
+	return duplicates
+}
+
+// ruleGroupDuplicateViolations returns the duplicates of the rules of a rule
+// group with the other rule groups of the tenant. The replaced rule group, as
+// namespace/name, is left out, as renaming a rule group or moving it to
+// another namespace replaces it.
+func ruleGroupDuplicateViolations(ctx context.Context, client *api_client, namespace, name, replaced string, rules []rulesFileRule) ([]string, error) {
+	var violations []string
+
+	if client.duplicate_rules_check == "" {
+		return violations, nil
+	}
+
+	groups, err := ruleGroupsWith(ctx, client, namespace, name, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := fmt.Sprintf("%s/%s", namespace, name)
+	if replaced != "" && replaced != owner {
+		parts := strings.SplitN(replaced, "/", 2)
+		if len(parts) == 2 {
+			var kept []rulesFileGroup
+			for _, group := range groups[parts[0]] {
+				if group.Name != parts[1] {
+					kept = append(kept, group)
+				}
+			}
+			groups[parts[0]] = kept
+		}
+	}
+	for _, duplicate := range findRuleDuplicates(groups) {
+		if SliceFind(duplicate.Owners, owner) {
+			violations = append(violations, duplicate.Message())
+		}
+	}
+
+	return violations, nil
+}
+
+// ruleDuplicatesCustomizeDiff checks the duplicates of a rule group at plan
+// time.
+func ruleDuplicatesCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, client *api_client) error {
+	if client.duplicate_rules_check != alertPolicyLevelError {
+		return nil
+	}
+	for _, key := range []string{"namespace", "name", "rule"} {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+	}
+	for i, rule := range d.Get("rule").([]interface{}) {
+		for key := range rule.(map[string]interface{}) {
+			if key == "labels" || key == "annotations" {
+				key += ".%"
+			}
+			if !d.NewValueKnown(fmt.Sprintf("rule.%d.%s", i, key)) {
+				return nil
+			}
+		}
+	}
+
+	return checkRuleGroupDuplicates(ctx, d, client)
+}
+
+// checkRuleGroupDuplicates checks the duplicates of a rule group before it is
+// sent to the ruler, to catch the rule groups created in the same run.
+func checkRuleGroupDuplicates(ctx context.Context, d resourceDataGetter, client *api_client) error {
+	if client.duplicate_rules_check != alertPolicyLevelError {
+		return nil
+	}
+
+	v, err := expandRuleGroupDuplicateRules(d, client)
+	if err != nil {
+		return err
+	}
+	violations, err := ruleGroupDuplicateViolations(ctx, client, d.Get("namespace").(string), d.Get("name").(string), ruleGroupReplacedID(d), v)
+	if err != nil {
+		return err
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	msg := "Duplicate rules:"
+	for _, v := range violations {
+		msg += "\n  - " + v
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+// ruleGroupReplacedID returns the ID of the rule group replaced by the planned
+// one, which is the prior ID at plan time and none when creating it.
+func ruleGroupReplacedID(d resourceDataGetter) string {
+	if v, ok := d.(interface{ Id() string }); ok {
+		return v.Id()
+	}
+	return ""
+}
+
+// ruleGroupDuplicateWarnings returns the duplicates of a rule group as warnings
+// when the check level is warning.
+func ruleGroupDuplicateWarnings(ctx context.Context, d resourceDataGetter, client *api_client) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if client.duplicate_rules_check != alertPolicyLevelWarning {
+		return diags
+	}
+
+	v, err := expandRuleGroupDuplicateRules(d, client)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	violations, err := ruleGroupDuplicateViolations(ctx, client, d.Get("namespace").(string), d.Get("name").(string), ruleGroupReplacedID(d), v)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	for _, v := range violations {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Duplicate rule",
+			Detail:   v,
+		})
+	}
+	return diags
+}
+
+// expandRuleGroupDuplicateRules returns the rules of an alerting or recording
+// rule group as sent to the ruler, with the default labels and the absent
+// alerts of the alerting rule groups.
+func expandRuleGroupDuplicateRules(d resourceDataGetter, client *api_client) ([]rulesFileRule, error) {
+	v := d.Get("rule").([]interface{})
+	if len(v) == 0 {
+		return nil, nil
+	}
+	if _, ok := v[0].(map[string]interface{})["alert"]; !ok {
+		return expandRuleGroupDependencyRules(d), nil
+	}
+
+	group, err := expandAlertingRuleGroup(client, d)
+	if err != nil {
+		return nil, err
+	}
+	var rules []rulesFileRule
+	for _, rule := range group.Rules {
+		rules = append(rules, rulesFileRule{
+			Alert:  rule.Alert,
+			Expr:   rule.Expr,
+			Labels: rule.Labels,
+		})
+	}
+
+	return rules, nil
+}
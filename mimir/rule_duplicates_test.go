@@ -0,0 +1,65 @@
+package mimir
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestRuleGroupDuplicateViolations(t *testing.T) {
+	rules := []rulesFileRule{{Alert: "InstanceDown", Expr: "up == 0"}}
+	client := &api_client{
+		duplicate_rules_check: alertPolicyLevelError,
+		rule_groups: &ruleGroupsCache{groups: map[string][]rulesFileGroup{
+			"namespace_1": {{Name: "alerts", Rules: rules}},
+		}},
+	}
+
+	tests := []struct {
+		name       string
+		namespace  string
+		group      string
+		replaced   string
+		violations []string
+	}{
+		{
+			name:      "update",
+			namespace: "namespace_1",
+			group:     "alerts",
+			replaced:  "namespace_1/alerts",
+		},
+		{
+			name:       "duplicate",
+			namespace:  "namespace_1",
+			group:      "other",
+			violations: []string{`alert "InstanceDown" is defined by the rule groups 'namespace_1/alerts' and 'namespace_1/other'`},
+		},
+		{
+			name:      "rename",
+			namespace: "namespace_1",
+			group:     "renamed",
+			replaced:  "namespace_1/alerts",
+		},
+		{
+			name:      "namespace change",
+			namespace: "namespace_2",
+			group:     "alerts",
+			replaced:  "namespace_1/alerts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := ruleGroupDuplicateViolations(context.Background(), client, tt.namespace, tt.group, tt.replaced, rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(violations) == 0 {
+				violations = nil
+			}
+			if !reflect.DeepEqual(violations, tt.violations) {
+				t.Fatalf("Got %v but expected %v", violations, tt.violations)
+			}
+		})
+	}
+}
@@ -2,10 +2,30 @@ package mimir
 
 import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+var tlsVersions = []string{"TLS10", "TLS11", "TLS12", "TLS13"}
+
 func tlsConfigFields() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
+		"ca": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "PEM encoded CA certificate to validate the server certificate with.",
+		},
+		"cert": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "PEM encoded certificate for client certificate authentication to the server.",
+		},
+		"key": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			DiffSuppressFunc: suppressMaskedSecretDiff,
+			Description:      "PEM encoded key for client certificate authentication to the server.",
+		},
 		"server_name": {
 			Type:        schema.TypeString,
 			Optional:    true,
@@ -17,26 +37,70 @@ func tlsConfigFields() map[string]*schema.Schema {
 			Default:     false,
 			Description: "Disable validation of the server certificate",
 		},
+		"min_version": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "Minimum acceptable TLS version, one of `TLS10`, `TLS11`, `TLS12` or `TLS13`.",
+			ValidateFunc: validation.StringInSlice(tlsVersions, false),
+		},
+		"max_version": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "Maximum acceptable TLS version, one of `TLS10`, `TLS11`, `TLS12` or `TLS13`.",
+			ValidateFunc: validation.StringInSlice(tlsVersions, false),
+		},
 	}
 }
 
-func httpConfigFields() map[string]*schema.Schema {
+// proxyConfigFields returns the proxy settings shared by the HTTP client and
+// its OAuth 2.0 configuration.
+func proxyConfigFields() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
-		"bearer_token": {
-			Type:      schema.TypeString,
-			Optional:  true,
-			Sensitive: true,
-		},
 		"proxy_url": {
 			Type:     schema.TypeString,
 			Optional: true,
 		},
+		"no_proxy": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Comma-separated list of IP addresses, CIDR notations and domain names to exclude from proxying.",
+		},
+		"proxy_from_environment": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Use the proxy URL indicated by the `HTTP_PROXY`, `HTTPS_PROXY` and `NO_PROXY` environment variables.",
+		},
+		"proxy_connect_header": {
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "Headers to send to proxies during CONNECT requests. Multiple values of a header are separated by commas.",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+	}
+}
+
+func httpConfigFields() map[string]*schema.Schema {
+	fields := map[string]*schema.Schema{
+		"bearer_token": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			DiffSuppressFunc: suppressMaskedSecretDiff,
+		},
 		"follow_redirects": {
 			Type:        schema.TypeBool,
 			Optional:    true,
 			Default:     true,
 			Description: "Configure whether HTTP requests follow HTTP 3xx redirects.",
 		},
+		"enable_http2": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Whether to enable HTTP2.",
+		},
 		"tls_config": {
 			Type:        schema.TypeList,
 			Optional:    true,
@@ -59,10 +123,11 @@ func httpConfigFields() map[string]*schema.Schema {
 						Description: "Sets the authentication type.",
 					},
 					"credentials": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Sensitive:   true,
-						Description: "Sets the credentials.",
+						Type:             schema.TypeString,
+						Optional:         true,
+						Sensitive:        true,
+						DiffSuppressFunc: suppressMaskedSecretDiff,
+						Description:      "Sets the credentials.",
 					},
 				},
 			},
@@ -79,9 +144,10 @@ func httpConfigFields() map[string]*schema.Schema {
 						Optional: true,
 					},
 					"password": {
-						Type:      schema.TypeString,
-						Optional:  true,
-						Sensitive: true,
+						Type:             schema.TypeString,
+						Optional:         true,
+						Sensitive:        true,
+						DiffSuppressFunc: suppressMaskedSecretDiff,
 					},
 				},
 			},
@@ -92,46 +158,59 @@ func httpConfigFields() map[string]*schema.Schema {
 			MaxItems:    1,
 			Description: "Set the OAuth 2.0 configuration.",
 			Elem: &schema.Resource{
-				Schema: map[string]*schema.Schema{
-					"client_id": {
-						Type:     schema.TypeString,
-						Optional: true,
-					},
-					"client_secret": {
-						Type:      schema.TypeString,
-						Optional:  true,
-						Sensitive: true,
-					},
-					"tls_config": {
-						Type:        schema.TypeList,
-						Optional:    true,
-						MaxItems:    1,
-						Description: "Configures the TLS settings.",
-						Elem: &schema.Resource{
-							Schema: tlsConfigFields(),
-						},
-					},
-					"token_url": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "The URL to fetch the token from.",
-					},
-					"endpoint_params": {
-						Type:        schema.TypeMap,
-						Optional:    true,
-						Description: "Parameters to append to the token URL.",
-						Elem:        &schema.Schema{Type: schema.TypeString},
-					},
-					"scopes": {
-						Type:        schema.TypeList,
-						Optional:    true,
-						Description: "Scopes for the token request.",
-						Elem:        &schema.Schema{Type: schema.TypeString},
-					},
-				},
+				Schema: oauth2ConfigFields(),
+			},
+		},
+	}
+	for k, v := range proxyConfigFields() {
+		fields[k] = v
+	}
+	return fields
+}
+
+func oauth2ConfigFields() map[string]*schema.Schema {
+	fields := map[string]*schema.Schema{
+		"client_id": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"client_secret": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			DiffSuppressFunc: suppressMaskedSecretDiff,
+		},
+		"tls_config": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Configures the TLS settings.",
+			Elem: &schema.Resource{
+				Schema: tlsConfigFields(),
 			},
 		},
+		"token_url": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The URL to fetch the token from.",
+		},
+		"endpoint_params": {
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Description: "Parameters to append to the token URL.",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"scopes": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "Scopes for the token request.",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+	}
+	for k, v := range proxyConfigFields() {
+		fields[k] = v
 	}
+	return fields
 }
 
 func emailConfigFields() map[string]*schema.Schema {
@@ -168,16 +247,18 @@ func emailConfigFields() map[string]*schema.Schema {
 			Description: "SMTP authentication username.",
 		},
 		"auth_password": {
-			Type:        schema.TypeString,
-			Optional:    true,
-			Sensitive:   true,
-			Description: "SMTP authentication password.",
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			DiffSuppressFunc: suppressMaskedSecretDiff,
+			Description:      "SMTP authentication password.",
 		},
 		"auth_secret": {
-			Type:        schema.TypeString,
-			Optional:    true,
-			Sensitive:   true,
-			Description: "SMTP authentication secret.",
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			DiffSuppressFunc: suppressMaskedSecretDiff,
+			Description:      "SMTP authentication secret.",
 		},
 		"auth_identity": {
 			Type:        schema.TypeString,
@@ -227,16 +308,18 @@ func pagerdutyConfigFields() map[string]*schema.Schema {
 			Description: "Whether to notify about resolved alerts.",
 		},
 		"service_key": {
-			Type:        schema.TypeString,
-			Optional:    true,
-			Sensitive:   true,
-			Description: "The PagerDuty integration key (when using PagerDuty integration type `Prometheus`).",
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			DiffSuppressFunc: suppressMaskedSecretDiff,
+			Description:      "The PagerDuty integration key (when using PagerDuty integration type `Prometheus`).",
 		},
 		"routing_key": {
-			Type:        schema.TypeString,
-			Optional:    true,
-			Sensitive:   true,
-			Description: "The PagerDuty integration key (when using PagerDuty integration type `Events API v2`).",
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			DiffSuppressFunc: suppressMaskedSecretDiff,
+			Description:      "The PagerDuty integration key (when using PagerDuty integration type `Events API v2`).",
 		},
 		"url": {
 			Type:        schema.TypeString,
@@ -343,10 +426,11 @@ func weChatConfigFields() map[string]*schema.Schema {
 			Description: "Whether to notify about resolved alerts.",
 		},
 		"api_secret": {
-			Type:        schema.TypeString,
-			Optional:    true,
-			Sensitive:   true,
-			Description: "The API key to use when talking to the WeChat API.",
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			DiffSuppressFunc: suppressMaskedSecretDiff,
+			Description:      "The API key to use when talking to the WeChat API.",
 		},
 		"api_url": {
 			Type:        schema.TypeString,
@@ -405,9 +489,11 @@ func webhookConfigFields() map[string]*schema.Schema {
 			Description: "Whether to notify about resolved alerts.",
 		},
 		"url": {
-			Type:        schema.TypeString,
-			Optional:    true,
-			Description: "The endpoint to send HTTP POST requests to.",
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			DiffSuppressFunc: suppressMaskedSecretDiff,
+			Description:      "The endpoint to send HTTP POST requests to.",
 		},
 		"max_alerts": {
 			Type:        schema.TypeInt,
@@ -444,14 +530,18 @@ func pushoverConfigFields() map[string]*schema.Schema {
 			Description: "The HTTP client's configuration.",
 		},
 		"user_key": {
-			Type:        schema.TypeString,
-			Optional:    true,
-			Description: "The recipient user's user key.",
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			DiffSuppressFunc: suppressMaskedSecretDiff,
+			Description:      "The recipient user's user key.",
 		},
 		"token": {
-			Type:        schema.TypeString,
-			Optional:    true,
-			Description: "The registered application's API token.",
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			DiffSuppressFunc: suppressMaskedSecretDiff,
+			Description:      "The registered application's API token.",
 		},
 		"title": {
 			Type:        schema.TypeString,
@@ -524,10 +614,11 @@ func opsgenieConfigFields() map[string]*schema.Schema {
 			Elem:        &schema.Schema{Type: schema.TypeString},
 		},
 		"api_key": {
-			Type:        schema.TypeString,
-			Optional:    true,
-			Sensitive:   true,
-			Description: "The API key to use when talking to the OpsGenie API.",
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			DiffSuppressFunc: suppressMaskedSecretDiff,
+			Description:      "The API key to use when talking to the OpsGenie API.",
 		},
 		"api_url": {
 			Type:        schema.TypeString,
@@ -626,9 +717,11 @@ func slackConfigFields() map[string]*schema.Schema {
 			Description: "The HTTP client's configuration.",
 		},
 		"api_url": {
-			Type:        schema.TypeString,
-			Optional:    true,
-			Description: "The Slack webhook URL. Defaults to global settings if none are set here.",
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			DiffSuppressFunc: suppressMaskedSecretDiff,
+			Description:      "The Slack webhook URL. Defaults to global settings if none are set here.",
 		},
 		"channel": {
 			Type:        schema.TypeString,
@@ -797,10 +890,11 @@ func telegramConfigFields() map[string]*schema.Schema {
 			Description: "The Telegram API URL. If not specified, default API URL will be used.",
 		},
 		"bot_token": {
-			Type:        schema.TypeString,
-			Optional:    true,
-			Sensitive:   true,
-			Description: "Telegram bot token",
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			DiffSuppressFunc: suppressMaskedSecretDiff,
+			Description:      "Telegram bot token",
 		},
 		"chat_id": {
 			Type:        schema.TypeString,
@@ -830,12 +924,411 @@ func telegramConfigFields() map[string]*schema.Schema {
 			Elem: &schema.Resource{
 				Schema: httpConfigFields(),
 			},
-			Description: "The HTTP client's configuration.",
+			Description: "The HTTP client's configuration.",
+		},
+	}
+}
+
+func victorOpsConfigFields() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"send_resolved": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Whether to notify about resolved alerts.",
+		},
+		"http_config": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: httpConfigFields(),
+			},
+			Description: "The HTTP client's configuration.",
+		},
+		"custom_fields": {
+			Type:     schema.TypeMap,
+			Optional: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"api_key": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			DiffSuppressFunc: suppressMaskedSecretDiff,
+			Description:      "The API key to use when talking to the VictorOps API.",
+		},
+		"api_url": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The VictorOps API URL.",
+		},
+		"routing_key": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "A key used to map the alert to a team.",
+		},
+		"message_type": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Describes the behavior of the alert (CRITICAL, WARNING, INFO).",
+		},
+		"state_message": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Contains long explanation of the alerted problem.",
+		},
+		"entity_display_name": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Contains summary of the alerted problem.",
+		},
+		"monitoring_tool": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The monitoring tool the state message is from.",
+		},
+	}
+}
+
+func snsConfigFields() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"send_resolved": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Whether to notify about resolved alerts.",
+		},
+		"http_config": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: httpConfigFields(),
+			},
+			Description: "The HTTP client's configuration.",
+		},
+		"attributes": {
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Description: "SNS message attributes.",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"api_url": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The SNS API URL. If not specified, the SNS API URL from the SNS SDK will be used.",
+		},
+		"topic_arn": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "SNS topic ARN. If not set, a value for the phone_number or target_arn should be set.",
+		},
+		"phone_number": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Phone number if message is delivered via SMS in E.164 format.",
+		},
+		"target_arn": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The mobile platform endpoint ARN if message is delivered via mobile notifications.",
+		},
+		"subject": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Subject line when the message is delivered to email endpoints.",
+		},
+		"message": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The message content of the SNS notification.",
+		},
+		"sigv4": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Configures AWS's Signature Verification 4 signing process to sign requests.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"region": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "The AWS region. If blank, the region from the default credentials chain is used.",
+					},
+					"access_key": {
+						Type:      schema.TypeString,
+						Optional:  true,
+						Sensitive: true,
+					},
+					"secret_key": {
+						Type:             schema.TypeString,
+						Optional:         true,
+						Sensitive:        true,
+						DiffSuppressFunc: suppressMaskedSecretDiff,
+					},
+					"profile": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "Named AWS profile used to authenticate.",
+					},
+					"role_arn": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "AWS Role ARN, an alternative to using AWS API keys.",
+					},
+				},
+			},
+		},
+	}
+}
+
+func discordConfigFields() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"send_resolved": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Whether to notify about resolved alerts.",
+		},
+		"webhook_url": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			DiffSuppressFunc: suppressMaskedSecretDiff,
+			Description:      "The Discord webhook URL.",
+		},
+		"title": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Message title template.",
+		},
+		"message": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Message body template.",
+		},
+		"http_config": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: httpConfigFields(),
+			},
+			Description: "The HTTP client's configuration.",
+		},
+	}
+}
+
+func msTeamsConfigFields() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"send_resolved": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Whether to notify about resolved alerts.",
+		},
+		"webhook_url": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			DiffSuppressFunc: suppressMaskedSecretDiff,
+			Description:      "The incoming webhook URL of the Microsoft Teams channel.",
+		},
+		"title": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Message title template.",
+		},
+		"summary": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Message summary template, shown in notifications.",
+		},
+		"text": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Message body template.",
+		},
+		"http_config": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: httpConfigFields(),
+			},
+			Description: "The HTTP client's configuration.",
+		},
+	}
+}
+
+func webexConfigFields() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"send_resolved": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Whether to notify about resolved alerts.",
+		},
+		"api_url": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The Webex Teams API URL. Defaults to global settings if none are set here.",
+		},
+		"room_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "ID of the Webex Teams room where to send the messages.",
+		},
+		"message": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Message template.",
+		},
+		"http_config": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: httpConfigFields(),
+			},
+			Description: "The HTTP client's configuration. The bot token is set with `authorization`.",
+		},
+	}
+}
+
+func rocketchatConfigFields() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"send_resolved": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Whether to notify about resolved alerts.",
+		},
+		"http_config": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: httpConfigFields(),
+			},
+			Description: "The HTTP client's configuration.",
+		},
+		"api_url": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The Rocket.Chat API URL. Defaults to global settings if none are set here.",
+		},
+		"token": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			DiffSuppressFunc: suppressMaskedSecretDiff,
+			Description:      "The Rocket.Chat personal access token. Defaults to global settings if none are set here.",
+		},
+		"token_id": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			DiffSuppressFunc: suppressMaskedSecretDiff,
+			Description:      "The ID of the Rocket.Chat user owning the token. Defaults to global settings if none are set here.",
+		},
+		"channel": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The channel or user to send notifications to.",
+		},
+		"color": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"title": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"title_link": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"text": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"fields": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"title": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"value": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"short": {
+						Type:     schema.TypeBool,
+						Optional: true,
+						Default:  false,
+					},
+				},
+			},
+		},
+		"short_fields": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"emoji": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"icon_url": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"image_url": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"thumb_url": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"link_names": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"actions": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"type": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"text": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"url": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"msg": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+				},
+			},
 		},
 	}
 }
 
-func victorOpsConfigFields() map[string]*schema.Schema {
+func jiraConfigFields() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"send_resolved": {
 			Type:        schema.TypeBool,
@@ -852,137 +1345,68 @@ func victorOpsConfigFields() map[string]*schema.Schema {
 			},
 			Description: "The HTTP client's configuration.",
 		},
-		"custom_fields": {
-			Type:     schema.TypeMap,
-			Optional: true,
-			Elem:     &schema.Schema{Type: schema.TypeString},
-		},
-		"api_key": {
-			Type:        schema.TypeString,
-			Optional:    true,
-			Sensitive:   true,
-			Description: "The API key to use when talking to the VictorOps API.",
-		},
 		"api_url": {
 			Type:        schema.TypeString,
 			Optional:    true,
-			Description: "The VictorOps API URL.",
+			Description: "The URL of the Jira REST API. Defaults to global settings if none are set here.",
 		},
-		"routing_key": {
+		"project": {
 			Type:        schema.TypeString,
 			Optional:    true,
-			Description: "A key used to map the alert to a team.",
+			Description: "The project key where issues are created.",
 		},
-		"message_type": {
+		"issue_type": {
 			Type:        schema.TypeString,
 			Optional:    true,
-			Description: "Describes the behavior of the alert (CRITICAL, WARNING, INFO).",
+			Description: "Type of the issue, e.g. `Bug`.",
 		},
-		"state_message": {
+		"summary": {
 			Type:        schema.TypeString,
 			Optional:    true,
-			Description: "Contains long explanation of the alerted problem.",
+			Description: "Issue summary template.",
 		},
-		"entity_display_name": {
+		"description": {
 			Type:        schema.TypeString,
 			Optional:    true,
-			Description: "Contains summary of the alerted problem.",
+			Description: "Issue description template.",
 		},
-		"monitoring_tool": {
+		"priority": {
 			Type:        schema.TypeString,
 			Optional:    true,
-			Description: "The monitoring tool the state message is from.",
+			Description: "Priority of the issue, e.g. `High`.",
 		},
-	}
-}
-
-func snsConfigFields() map[string]*schema.Schema {
-	return map[string]*schema.Schema{
-		"send_resolved": {
-			Type:        schema.TypeBool,
+		"labels": {
+			Type:        schema.TypeList,
 			Optional:    true,
-			Default:     true,
-			Description: "Whether to notify about resolved alerts.",
-		},
-		"http_config": {
-			Type:     schema.TypeList,
-			Optional: true,
-			MaxItems: 1,
-			Elem: &schema.Resource{
-				Schema: httpConfigFields(),
-			},
-			Description: "The HTTP client's configuration.",
+			Description: "Labels to be added to the issue.",
+			Elem:        &schema.Schema{Type: schema.TypeString},
 		},
-		"attributes": {
+		"fields": {
 			Type:        schema.TypeMap,
 			Optional:    true,
-			Description: "SNS message attributes.",
+			Description: "Other issue and custom fields, keyed by field ID. Values which are valid JSON, e.g. `jsonencode({ value = \"foo\" })` or `\"42\"`, are sent as JSON, numbers included; other values are sent as strings. Use `jsonencode(\"42\")` to send a number as a string.",
 			Elem:        &schema.Schema{Type: schema.TypeString},
 		},
-		"api_url": {
-			Type:        schema.TypeString,
-			Optional:    true,
-			Description: "The SNS API URL. If not specified, the SNS API URL from the SNS SDK will be used.",
-		},
-		"topic_arn": {
-			Type:        schema.TypeString,
-			Optional:    true,
-			Description: "SNS topic ARN. If not set, a value for the phone_number or target_arn should be set.",
-		},
-		"phone_number": {
-			Type:        schema.TypeString,
-			Optional:    true,
-			Description: "Phone number if message is delivered via SMS in E.164 format.",
-		},
-		"target_arn": {
+		"reopen_transition": {
 			Type:        schema.TypeString,
 			Optional:    true,
-			Description: "The mobile platform endpoint ARN if message is delivered via mobile notifications.",
+			Description: "Name of the workflow transition to reopen an issue. The target status must not have the category `done`.",
 		},
-		"subject": {
+		"resolve_transition": {
 			Type:        schema.TypeString,
 			Optional:    true,
-			Description: "Subject line when the message is delivered to email endpoints.",
+			Description: "Name of the workflow transition to resolve an issue. The target status must have the category `done`.",
 		},
-		"message": {
+		"wont_fix_resolution": {
 			Type:        schema.TypeString,
 			Optional:    true,
-			Description: "The message content of the SNS notification.",
+			Description: "If the issue has this resolution, it is not reopened.",
 		},
-		"sigv4": {
-			Type:        schema.TypeList,
-			Optional:    true,
-			MaxItems:    1,
-			Description: "Configures AWS's Signature Verification 4 signing process to sign requests.",
-			Elem: &schema.Resource{
-				Schema: map[string]*schema.Schema{
-					"region": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "The AWS region. If blank, the region from the default credentials chain is used.",
-					},
-					"access_key": {
-						Type:      schema.TypeString,
-						Optional:  true,
-						Sensitive: true,
-					},
-					"secret_key": {
-						Type:      schema.TypeString,
-						Optional:  true,
-						Sensitive: true,
-					},
-					"profile": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "Named AWS profile used to authenticate.",
-					},
-					"role_arn": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "AWS Role ARN, an alternative to using AWS API keys.",
-					},
-				},
-			},
+		"reopen_duration": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "If the issue was resolved for longer than this duration, a new issue is created instead of reopening it.",
+			ValidateFunc: validateDuration,
 		},
 	}
 }
@@ -1031,29 +1455,57 @@ func resourceMimirAlertmanagerConfigSchemaV1() map[string]*schema.Schema {
 						Type:     schema.TypeString,
 						Optional: true,
 					},
+					"webex_api_url": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"jira_api_url": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"rocketchat_api_url": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"rocketchat_token": {
+						Type:             schema.TypeString,
+						Optional:         true,
+						Sensitive:        true,
+						DiffSuppressFunc: suppressMaskedSecretDiff,
+					},
+					"rocketchat_token_id": {
+						Type:             schema.TypeString,
+						Optional:         true,
+						Sensitive:        true,
+						DiffSuppressFunc: suppressMaskedSecretDiff,
+					},
 					"slack_api_url": {
-						Type:      schema.TypeString,
-						Optional:  true,
-						Sensitive: true,
+						Type:             schema.TypeString,
+						Optional:         true,
+						Sensitive:        true,
+						DiffSuppressFunc: suppressMaskedSecretDiff,
 					},
 					"opsgenie_api_key": {
-						Type:      schema.TypeString,
-						Optional:  true,
-						Sensitive: true,
+						Type:             schema.TypeString,
+						Optional:         true,
+						Sensitive:        true,
+						DiffSuppressFunc: suppressMaskedSecretDiff,
 					},
 					"wechat_api_secret": {
-						Type:      schema.TypeString,
-						Optional:  true,
-						Sensitive: true,
+						Type:             schema.TypeString,
+						Optional:         true,
+						Sensitive:        true,
+						DiffSuppressFunc: suppressMaskedSecretDiff,
 					},
 					"wechat_api_corp_id": {
 						Type:     schema.TypeString,
 						Optional: true,
 					},
 					"victorops_api_key": {
-						Type:      schema.TypeString,
-						Optional:  true,
-						Sensitive: true,
+						Type:             schema.TypeString,
+						Optional:         true,
+						Sensitive:        true,
+						DiffSuppressFunc: suppressMaskedSecretDiff,
 					},
 					"smtp_from": {
 						Type:        schema.TypeString,
@@ -1076,16 +1528,18 @@ func resourceMimirAlertmanagerConfigSchemaV1() map[string]*schema.Schema {
 						Description: "SMTP Auth using CRAM-MD5, LOGIN and PLAIN. If empty, Alertmanager doesn't authenticate to the SMTP server.",
 					},
 					"smtp_auth_password": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Sensitive:   true,
-						Description: "SMTP Auth using LOGIN and PLAIN.",
+						Type:             schema.TypeString,
+						Optional:         true,
+						Sensitive:        true,
+						DiffSuppressFunc: suppressMaskedSecretDiff,
+						Description:      "SMTP Auth using LOGIN and PLAIN.",
 					},
 					"smtp_auth_secret": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Sensitive:   true,
-						Description: "SMTP Auth using CRAM-MD5.",
+						Type:             schema.TypeString,
+						Optional:         true,
+						Sensitive:        true,
+						DiffSuppressFunc: suppressMaskedSecretDiff,
+						Description:      "SMTP Auth using CRAM-MD5.",
 					},
 					"smtp_auth_identity": {
 						Type:        schema.TypeString,
@@ -1231,12 +1685,25 @@ func resourceMimirAlertmanagerConfigSchemaV1() map[string]*schema.Schema {
 										},
 									},
 								},
+								"location": {
+									Type:         schema.TypeString,
+									Optional:     true,
+									Description:  "Time zone of the IANA time zone database the interval is evaluated in, e.g. `Europe/Paris`. Defaults to UTC.",
+									ValidateFunc: validateTimeZone,
+								},
 							},
 						},
 					},
 				},
 			},
 		},
+		"time_intervals_key": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "mute_time_intervals",
+			Description:  "Top-level key the `time_interval` blocks are written to, either `mute_time_intervals` or `time_intervals`, which newer alertmanager versions use instead. Configs defining time intervals under both keys cannot be read.",
+			ValidateFunc: validation.StringInSlice([]string{"mute_time_intervals", "time_intervals"}, false),
+		},
 		"receiver": {
 			Type:        schema.TypeList,
 			Required:    true,
@@ -1328,6 +1795,46 @@ func resourceMimirAlertmanagerConfigSchemaV1() map[string]*schema.Schema {
 							Schema: snsConfigFields(),
 						},
 					},
+					"discord_configs": {
+						Type:     schema.TypeList,
+						Optional: true,
+						MaxItems: 1,
+						Elem: &schema.Resource{
+							Schema: discordConfigFields(),
+						},
+					},
+					"msteams_configs": {
+						Type:     schema.TypeList,
+						Optional: true,
+						MaxItems: 1,
+						Elem: &schema.Resource{
+							Schema: msTeamsConfigFields(),
+						},
+					},
+					"webex_configs": {
+						Type:     schema.TypeList,
+						Optional: true,
+						MaxItems: 1,
+						Elem: &schema.Resource{
+							Schema: webexConfigFields(),
+						},
+					},
+					"rocketchat_configs": {
+						Type:     schema.TypeList,
+						Optional: true,
+						MaxItems: 1,
+						Elem: &schema.Resource{
+							Schema: rocketchatConfigFields(),
+						},
+					},
+					"jira_configs": {
+						Type:     schema.TypeList,
+						Optional: true,
+						MaxItems: 1,
+						Elem: &schema.Resource{
+							Schema: jiraConfigFields(),
+						},
+					},
 				},
 			},
 		},
@@ -1437,6 +1944,30 @@ func resourceMimirAlertmanagerConfigSchemaV1() map[string]*schema.Schema {
 				},
 			},
 		},
+		"trust_server_secrets": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "When the alertmanager API returns masked secrets and the actual ones are unknown, e.g. after an import, assume they match the configured secrets instead of planning an update.",
+		},
+		"verify": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Wait until the alertmanager runs the new config after create or update, and report its error if it does not.",
+		},
+		"verify_timeout": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "1m",
+			Description:  "How long to wait for the alertmanager to run the new config when `verify` is set.",
+			ValidateFunc: validateDuration,
+		},
+		"secrets_hash": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "A hash of the configured secrets, used to detect changes to secrets the alertmanager API returns masked.",
+		},
 		"templates": {
 			Type:        schema.TypeList,
 			Optional:    true,
@@ -1498,6 +2029,28 @@ func dataSourceMimirAlertmanagerConfigSchemaV1() map[string]*schema.Schema {
 						Type:     schema.TypeString,
 						Computed: true,
 					},
+					"webex_api_url": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"jira_api_url": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"rocketchat_api_url": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"rocketchat_token": {
+						Type:      schema.TypeString,
+						Computed:  true,
+						Sensitive: true,
+					},
+					"rocketchat_token_id": {
+						Type:      schema.TypeString,
+						Computed:  true,
+						Sensitive: true,
+					},
 					"slack_api_url": {
 						Type:      schema.TypeString,
 						Computed:  true,
@@ -1697,12 +2250,22 @@ func dataSourceMimirAlertmanagerConfigSchemaV1() map[string]*schema.Schema {
 										},
 									},
 								},
+								"location": {
+									Type:        schema.TypeString,
+									Computed:    true,
+									Description: "Time zone of the IANA time zone database the interval is evaluated in, e.g. `Europe/Paris`. Defaults to UTC.",
+								},
 							},
 						},
 					},
 				},
 			},
 		},
+		"time_intervals_key": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Top-level key the `time_interval` blocks are read from, either `mute_time_intervals` or `time_intervals`. Configs defining time intervals under both keys cannot be read.",
+		},
 		"receiver": {
 			Type:        schema.TypeList,
 			Computed:    true,
@@ -1784,6 +2347,41 @@ func dataSourceMimirAlertmanagerConfigSchemaV1() map[string]*schema.Schema {
 							Schema: snsConfigFields(),
 						},
 					},
+					"discord_configs": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem: &schema.Resource{
+							Schema: discordConfigFields(),
+						},
+					},
+					"msteams_configs": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem: &schema.Resource{
+							Schema: msTeamsConfigFields(),
+						},
+					},
+					"webex_configs": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem: &schema.Resource{
+							Schema: webexConfigFields(),
+						},
+					},
+					"rocketchat_configs": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem: &schema.Resource{
+							Schema: rocketchatConfigFields(),
+						},
+					},
+					"jira_configs": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem: &schema.Resource{
+							Schema: jiraConfigFields(),
+						},
+					},
 				},
 			},
 		},
@@ -1900,3 +2498,26 @@ func dataSourceMimirAlertmanagerConfigSchemaV1() map[string]*schema.Schema {
 		},
 	}
 }
+
+func dataSourceMimirAlertmanagerConfigDocumentSchemaV1() map[string]*schema.Schema {
+	documentSchema := resourceMimirAlertmanagerConfigSchemaV1()
+
+	// Only relevant when the config is applied.
+	for _, k := range []string{"trust_server_secrets", "secrets_hash", "verify", "verify_timeout"} {
+		delete(documentSchema, k)
+	}
+
+	documentSchema["alertmanager_config"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Sensitive:   true,
+		Description: "The alertmanager config as YAML, as loaded by a standalone alertmanager.",
+	}
+	documentSchema["content"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Sensitive:   true,
+		Description: "The alertmanager config and its template files as YAML, as accepted by the Mimir alertmanager API.",
+	}
+	return documentSchema
+}
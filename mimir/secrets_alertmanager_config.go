@@ -0,0 +1,153 @@
+package mimir
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Values returned by the alertmanager API in place of a secret, depending on
+// the Mimir version and configuration.
+var maskedSecretValues = []string{"<secret>", "<redacted>", "<hidden>"}
+
+// isMaskedSecret reports whether the value is a mask or an obfuscated
+// secret instead of the actual secret.
+func isMaskedSecret(v string) bool {
+	if v == "" {
+		return false
+	}
+	if SliceFind(maskedSecretValues, v) {
+		return true
+	}
+	return strings.Trim(v, "*") == ""
+}
+
+// suppressMaskedSecretDiff hides the difference between a masked secret
+// read from the API and the configured one when the user chose to trust
+// server-side secrets.
+func suppressMaskedSecretDiff(k, old, new string, d *schema.ResourceData) bool {
+	trust, ok := d.GetOk("trust_server_secrets")
+	return ok && trust.(bool) && isMaskedSecret(old)
+}
+
+// restoreMaskedSecrets replaces every masked secret of remote, as returned by
+// the API, with the secret at the same place in local. Fields holding a
+// secret are tagged with `secret:"true"`.
+func restoreMaskedSecrets(remote, local interface{}) {
+	walkSecrets(reflect.ValueOf(remote), reflect.ValueOf(local), func(remote, local reflect.Value) {
+		if isMaskedSecret(remote.String()) && local.String() != "" {
+			remote.SetString(local.String())
+		}
+	})
+}
+
+// hasMaskedSecrets reports whether any secret of v is masked.
+func hasMaskedSecrets(v interface{}) bool {
+	masked := false
+	walkSecrets(reflect.ValueOf(v), reflect.ValueOf(v), func(remote, local reflect.Value) {
+		masked = masked || isMaskedSecret(remote.String())
+	})
+	return masked
+}
+
+// hashSecrets returns a hash of all the secrets of v, so that changes can be
+// detected without comparing the secrets themselves.
+func hashSecrets(v interface{}) string {
+	var secrets []string
+	walkSecrets(reflect.ValueOf(v), reflect.ValueOf(v), func(remote, local reflect.Value) {
+		if remote.String() != "" {
+			secrets = append(secrets, remote.String())
+		}
+	})
+	return hashString(strings.Join(secrets, "\n"))
+}
+
+// walkSecrets walks remote and local side by side and calls fn on every pair
+// of secret string fields. Slice elements are matched by their Name field
+// when they have one, by index otherwise.
+func walkSecrets(remote, local reflect.Value, fn func(remote, local reflect.Value)) {
+	if !remote.IsValid() || !local.IsValid() {
+		return
+	}
+
+	switch remote.Kind() {
+	case reflect.Ptr:
+		if remote.IsNil() || local.IsNil() {
+			return
+		}
+		walkSecrets(remote.Elem(), local.Elem(), fn)
+	case reflect.Struct:
+		t := remote.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" && !field.Anonymous {
+				continue
+			}
+			if field.Tag.Get("secret") == "true" {
+				walkSecretValues(remote.Field(i), local.Field(i), fn)
+				continue
+			}
+			walkSecrets(remote.Field(i), local.Field(i), fn)
+		}
+	case reflect.Slice:
+		localByName := make(map[string]reflect.Value)
+		for i := 0; i < local.Len(); i++ {
+			if name, ok := secretElemName(local.Index(i)); ok {
+				localByName[name] = local.Index(i)
+			}
+		}
+		for i := 0; i < remote.Len(); i++ {
+			if name, ok := secretElemName(remote.Index(i)); ok {
+				walkSecrets(remote.Index(i), localByName[name], fn)
+			} else if i < local.Len() {
+				walkSecrets(remote.Index(i), local.Index(i), fn)
+			}
+		}
+	}
+}
+
+// walkSecretValues calls fn on the secret strings of a field tagged as
+// secret, either a string or a map of string lists such as headers.
+func walkSecretValues(remote, local reflect.Value, fn func(remote, local reflect.Value)) {
+	switch remote.Kind() {
+	case reflect.String:
+		fn(remote, local)
+	case reflect.Map:
+		// Walk the keys in order, so that the secrets hash is stable.
+		keys := remote.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, key := range keys {
+			remoteValues := remote.MapIndex(key)
+			localValues := reflect.Zero(remoteValues.Type())
+			if local.Len() > 0 && local.MapIndex(key).IsValid() {
+				localValues = local.MapIndex(key)
+			}
+			for j := 0; j < remoteValues.Len(); j++ {
+				if j < localValues.Len() {
+					fn(remoteValues.Index(j), localValues.Index(j))
+				} else {
+					fn(remoteValues.Index(j), reflect.ValueOf(""))
+				}
+			}
+		}
+	}
+}
+
+func secretElemName(v reflect.Value) (string, bool) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", false
+	}
+	name := v.FieldByName("Name")
+	if !name.IsValid() || name.Kind() != reflect.String {
+		return "", false
+	}
+	return name.String(), true
+}
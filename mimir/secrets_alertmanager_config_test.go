@@ -0,0 +1,183 @@
+package mimir
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func secretsTestConfig(receivers ...*receiver) *alertmanagerConfig {
+	return &alertmanagerConfig{
+		Global:    &globalConfig{SMTPAuthPassword: "smtp-password"},
+		Receivers: receivers,
+	}
+}
+
+func secretsTestWebhook(name, url, proxyAuth string) *receiver {
+	webhook := &webhookConfig{URL: url}
+	if proxyAuth != "" {
+		webhook.HTTPConfig = &httpClientConfig{
+			proxyConfig: proxyConfig{
+				ProxyConnectHeader: map[string][]string{"Proxy-Authorization": {proxyAuth}},
+			},
+		}
+	}
+	return &receiver{Name: name, WebhookConfigs: []*webhookConfig{webhook}}
+}
+
+func TestIsMaskedSecret(t *testing.T) {
+	tests := map[string]bool{
+		"":           false,
+		"<secret>":   true,
+		"<redacted>": true,
+		"<hidden>":   true,
+		"****":       true,
+		"s3cr3t":     false,
+		"<secret":    false,
+		"**s3cr3t**": false,
+	}
+
+	for v, expected := range tests {
+		if got := isMaskedSecret(v); got != expected {
+			t.Errorf("isMaskedSecret(%q) = %v but expected %v", v, got, expected)
+		}
+	}
+}
+
+func TestRestoreMaskedSecrets(t *testing.T) {
+	tests := []struct {
+		name     string
+		remote   *alertmanagerConfig
+		local    *alertmanagerConfig
+		expected *alertmanagerConfig
+		masked   bool
+	}{
+		{
+			name: "unmasked",
+			remote: secretsTestConfig(
+				secretsTestWebhook("a", "https://a.example.com", ""),
+			),
+			local: secretsTestConfig(
+				secretsTestWebhook("a", "https://old.example.com", ""),
+			),
+			expected: secretsTestConfig(
+				secretsTestWebhook("a", "https://a.example.com", ""),
+			),
+		},
+		{
+			name: "masked",
+			remote: &alertmanagerConfig{
+				Global: &globalConfig{SMTPAuthPassword: "<secret>"},
+				Receivers: []*receiver{
+					secretsTestWebhook("a", "<secret>", ""),
+				},
+			},
+			local: secretsTestConfig(
+				secretsTestWebhook("a", "https://a.example.com", ""),
+			),
+			expected: secretsTestConfig(
+				secretsTestWebhook("a", "https://a.example.com", ""),
+			),
+			masked: true,
+		},
+		{
+			name: "receivers in a different order",
+			remote: secretsTestConfig(
+				secretsTestWebhook("b", "<secret>", ""),
+				secretsTestWebhook("a", "<secret>", ""),
+			),
+			local: secretsTestConfig(
+				secretsTestWebhook("a", "https://a.example.com", ""),
+				secretsTestWebhook("b", "https://b.example.com", ""),
+			),
+			expected: secretsTestConfig(
+				secretsTestWebhook("b", "https://b.example.com", ""),
+				secretsTestWebhook("a", "https://a.example.com", ""),
+			),
+			masked: true,
+		},
+		{
+			name: "proxy connect header",
+			remote: secretsTestConfig(
+				secretsTestWebhook("a", "https://a.example.com", "<secret>"),
+			),
+			local: secretsTestConfig(
+				secretsTestWebhook("a", "https://a.example.com", "Basic dXNlcjpwYXNz"),
+			),
+			expected: secretsTestConfig(
+				secretsTestWebhook("a", "https://a.example.com", "Basic dXNlcjpwYXNz"),
+			),
+			masked: true,
+		},
+		{
+			name: "empty local value",
+			remote: secretsTestConfig(
+				secretsTestWebhook("a", "<secret>", "<secret>"),
+			),
+			local: secretsTestConfig(
+				secretsTestWebhook("a", "", ""),
+			),
+			expected: secretsTestConfig(
+				secretsTestWebhook("a", "<secret>", "<secret>"),
+			),
+			masked: true,
+		},
+		{
+			name: "receiver missing locally",
+			remote: secretsTestConfig(
+				secretsTestWebhook("a", "<secret>", ""),
+			),
+			local: secretsTestConfig(),
+			expected: secretsTestConfig(
+				secretsTestWebhook("a", "<secret>", ""),
+			),
+			masked: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasMaskedSecrets(tt.remote); got != tt.masked {
+				t.Fatalf("hasMaskedSecrets() = %v but expected %v", got, tt.masked)
+			}
+
+			restoreMaskedSecrets(tt.remote, tt.local)
+			if !reflect.DeepEqual(tt.remote, tt.expected) {
+				t.Fatalf("Got %s but expected %s", mustMarshalYAML(t, tt.remote), mustMarshalYAML(t, tt.expected))
+			}
+		})
+	}
+}
+
+func TestHashSecrets(t *testing.T) {
+	base := hashSecrets(secretsTestConfig(secretsTestWebhook("a", "https://a.example.com", "Basic dXNlcjpwYXNz")))
+
+	if got := hashSecrets(secretsTestConfig(secretsTestWebhook("a", "https://a.example.com", "Basic dXNlcjpwYXNz"))); got != base {
+		t.Errorf("Got a different hash for the same secrets")
+	}
+	if got := hashSecrets(secretsTestConfig(secretsTestWebhook("a", "https://b.example.com", "Basic dXNlcjpwYXNz"))); got == base {
+		t.Errorf("Got the same hash for a changed URL")
+	}
+	if got := hashSecrets(secretsTestConfig(secretsTestWebhook("a", "https://a.example.com", "Basic b3RoZXI6cGFzcw=="))); got == base {
+		t.Errorf("Got the same hash for a changed proxy connect header")
+	}
+
+	headers := secretsTestWebhook("a", "https://a.example.com", "Basic dXNlcjpwYXNz")
+	headers.WebhookConfigs[0].HTTPConfig.ProxyConnectHeader["X-Token"] = []string{"token"}
+	headers.WebhookConfigs[0].HTTPConfig.ProxyConnectHeader["X-Other-Token"] = []string{"other", "token"}
+	multi := hashSecrets(secretsTestConfig(headers))
+	for i := 0; i < 50; i++ {
+		if got := hashSecrets(secretsTestConfig(headers)); got != multi {
+			t.Fatalf("Got a different hash for the same proxy connect headers")
+		}
+	}
+}
+
+func mustMarshalYAML(t *testing.T, v interface{}) string {
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
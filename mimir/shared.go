@@ -2,11 +2,26 @@ package mimir
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql/parser"
+	"gopkg.in/yaml.v3"
+	"net/http"
 	"regexp"
+	"sort"
+	"sync"
+	"time"
+	// Embed the time zone database so that time zones are validated the
+	// same way whatever the system the provider runs on.
+	_ "time/tzdata"
 	"unicode/utf8"
 )
 
@@ -25,14 +40,46 @@ func jsonPrettyPrint(input []byte) string {
 	return out.String()
 }
 
+// hashString returns the hex encoded SHA-256 sum of the given string.
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
 func handleHTTPError(err error, body string, url, baseMsg string) error {
 	if err != nil {
-		return fmt.Errorf("%s %v", baseMsg, err)
+		return fmt.Errorf("%s %w", baseMsg, err)
 	}
 
 	return nil
 }
 
+// isNotFoundError reports whether the error is an API error with a 404 status
+// code.
+func isNotFoundError(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// diagFromErr returns the diagnostics of an error. The API errors get a detail
+// telling the failed request and what to check.
+func diagFromErr(err error) diag.Diagnostics {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		return diag.FromErr(err)
+	}
+
+	detail := fmt.Sprintf("The %s answered %d to %s %s.", apiErr.Component, apiErr.StatusCode, apiErr.Method, apiErr.URL)
+	if hint := apiErr.Hint(); hint != "" {
+		detail += " " + hint
+	}
+	return diag.Diagnostics{diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  err.Error(),
+		Detail:   detail,
+	}}
+}
+
 // Array to String Array
 func expandStringArray(v []interface{}) []string {
 	var m []string
@@ -75,6 +122,67 @@ func validatePromQLExpr(v interface{}, k string) (ws []string, errors []error) {
 	return
 }
 
+// enforceMatchers returns the expression with the given label matchers set
+// on every vector selector, replacing any matcher on the same labels, the
+// same way prom-label-proxy does.
+func enforceMatchers(expr string, matchers map[string]string) (string, error) {
+	if len(matchers) == 0 {
+		return expr, nil
+	}
+
+	node, err := parser.ParseExpr(expr)
+	if err != nil {
+		return "", fmt.Errorf("Invalid PromQL expression %q: %v", expr, err)
+	}
+
+	names := make([]string, 0, len(matchers))
+	for name := range matchers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parser.Inspect(node, func(n parser.Node, _ []parser.Node) error {
+		vs, ok := n.(*parser.VectorSelector)
+		if !ok {
+			return nil
+		}
+
+		var lms []*labels.Matcher
+		for _, m := range vs.LabelMatchers {
+			if _, ok := matchers[m.Name]; !ok {
+				lms = append(lms, m)
+			}
+		}
+		for _, name := range names {
+			lms = append(lms, labels.MustNewMatcher(labels.MatchEqual, name, matchers[name]))
+		}
+		vs.LabelMatchers = lms
+
+		return nil
+	})
+
+	return node.String(), nil
+}
+
+// restoreEnforcedExprs keeps the rule expressions of the state when the ruler
+// returns them with the enforced matchers, so that the plan compares against
+// the configured expressions.
+func restoreEnforcedExprs(d *schema.ResourceData, key string, rules []map[string]interface{}, matchers map[string]string) {
+	if len(matchers) == 0 {
+		return
+	}
+
+	for i, rule := range rules {
+		old, ok := d.GetOk(fmt.Sprintf("%s.%d.expr", key, i))
+		if !ok {
+			continue
+		}
+		if expr, err := enforceMatchers(old.(string), matchers); err == nil && expr == rule["expr"] {
+			rule["expr"] = old
+		}
+	}
+}
+
 func validateLabels(v interface{}, k string) (ws []string, errors []error) {
 	m := v.(map[string]interface{})
 	for lname, lvalue := range m {
@@ -104,6 +212,18 @@ func validateAnnotations(v interface{}, k string) (ws []string, errors []error)
 	return
 }
 
+func validateDefaultAlertLabels(v interface{}, k string) (ws []string, errors []error) {
+	ws, errors = validateLabels(v, k)
+	_, tmplErrors := validateAlertingRuleDefaultTemplates(v, k)
+	return ws, append(errors, tmplErrors...)
+}
+
+func validateDefaultAlertAnnotations(v interface{}, k string) (ws []string, errors []error) {
+	ws, errors = validateAnnotations(v, k)
+	_, tmplErrors := validateAlertingRuleDefaultTemplates(v, k)
+	return ws, append(errors, tmplErrors...)
+}
+
 func validateDuration(v interface{}, k string) (ws []string, errors []error) {
 	value := v.(string)
 
@@ -118,6 +238,26 @@ func validateDuration(v interface{}, k string) (ws []string, errors []error) {
 	return
 }
 
+func validateTimeZone(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if value == "" {
+		return
+	}
+
+	// The alertmanager refuses the local time zone of its host.
+	if value == "Local" {
+		errors = append(errors, fmt.Errorf("\"%s\": location can't be Local", k))
+		return
+	}
+
+	if _, err := time.LoadLocation(value); err != nil {
+		errors = append(errors, fmt.Errorf("\"%s\": %v", k, err))
+	}
+
+	return
+}
+
 // SliceFind takes a slice and looks for an element in it. If found it will
 // return true otherwise false.
 func SliceFind(slice []string, val string) bool {
@@ -128,3 +268,165 @@ func SliceFind(slice []string, val string) bool {
 	}
 	return false
 }
+
+// computedRulesEqual compares the computed rules of the state with the
+// generated ones.
+func computedRulesEqual(old []interface{}, rules []map[string]interface{}) bool {
+	if len(old) != len(rules) {
+		return false
+	}
+
+	for i, raw := range old {
+		o := raw.(map[string]interface{})
+		for key, val := range rules[i] {
+			switch val := val.(type) {
+			case string:
+				if o[key] != val {
+					return false
+				}
+			case map[string]string:
+				m, _ := o[key].(map[string]interface{})
+				if len(m) != len(val) {
+					return false
+				}
+				for k, v := range val {
+					if m[k] != v {
+						return false
+					}
+				}
+			}
+		}
+		for key, val := range o {
+			if m, ok := val.(map[string]interface{}); ok && len(m) > 0 {
+				if _, ok := rules[i][key]; !ok {
+					return false
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// readRuleGroups returns the rule groups of the tenant by namespace.
+func readRuleGroups(ctx context.Context, client *api_client) (map[string][]rulesFileGroup, error) {
+	var headers map[string]string
+	path := "/config/v1/rules"
+	jobraw, err := client.send_request(ctx, "ruler", "GET", path, "", headers)
+
+	baseMsg := "Cannot read rule groups -"
+	fullurl := fmt.Sprintf("%s%s", client.uri, path)
+	err = handleHTTPError(err, jobraw, fullurl, baseMsg)
+	if err != nil {
+		// The ruler answers 404 when the tenant has no rule group.
+		if isNotFoundError(err) {
+			return make(map[string][]rulesFileGroup), nil
+		}
+		return nil, err
+	}
+
+	data := make(map[string][]rulesFileGroup)
+	if err := yaml.Unmarshal([]byte(jobraw), &data); err != nil {
+		return nil, fmt.Errorf("Unable to decode rule groups: %v", err)
+	}
+
+	return data, nil
+}
+
+// ruleGroupsCache holds the rule groups of the tenant, read once per run by
+// the rule dependencies and duplicates checks instead of once per resource.
+// The groups written by the provider are updated in place, so that the checks
+// see the groups created earlier in the run.
+type ruleGroupsCache struct {
+	mu     sync.Mutex
+	groups map[string][]rulesFileGroup
+}
+
+// get returns a copy of the rule groups, reading them on the first call.
+func (c *ruleGroupsCache) get(ctx context.Context, client *api_client) (map[string][]rulesFileGroup, error) {
+	if c == nil {
+		return readRuleGroups(ctx, client)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.groups == nil {
+		groups, err := readRuleGroups(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		c.groups = groups
+	}
+
+	groups := make(map[string][]rulesFileGroup, len(c.groups))
+	for namespace, v := range c.groups {
+		groups[namespace] = append([]rulesFileGroup(nil), v...)
+	}
+	return groups, nil
+}
+
+// set updates a rule group from the definition sent to the ruler.
+func (c *ruleGroupsCache) set(namespace, data string) {
+	if c == nil {
+		return
+	}
+
+	var group rulesFileGroup
+	if err := yaml.Unmarshal([]byte(data), &group); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.groups == nil {
+		return
+	}
+	for i := range c.groups[namespace] {
+		if c.groups[namespace][i].Name == group.Name {
+			c.groups[namespace][i] = group
+			return
+		}
+	}
+	c.groups[namespace] = append(c.groups[namespace], group)
+}
+
+// delete removes a rule group deleted from the ruler.
+func (c *ruleGroupsCache) delete(namespace, name string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var groups []rulesFileGroup
+	for _, group := range c.groups[namespace] {
+		if group.Name != name {
+			groups = append(groups, group)
+		}
+	}
+	if c.groups != nil {
+		c.groups[namespace] = groups
+	}
+}
+
+// ruleGroupsWith returns the rule groups of the tenant with a rule group
+// replaced by the given rules, or added if it does not exist yet.
+func ruleGroupsWith(ctx context.Context, client *api_client, namespace, name string, rules []rulesFileRule) (map[string][]rulesFileGroup, error) {
+	groups, err := client.rule_groups.get(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, group := range groups[namespace] {
+		if group.Name == name {
+			groups[namespace][i].Rules = rules
+			return groups, nil
+		}
+	}
+	groups[namespace] = append(groups[namespace], rulesFileGroup{Name: name, Rules: rules})
+
+	return groups, nil
+}
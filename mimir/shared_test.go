@@ -1,6 +1,7 @@
 package mimir
 
 import (
+	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -35,7 +36,7 @@ func testAccCheckMimirRuleGroupExists(n string, name string, client *api_client)
 		/* Make a throw-away API object to read from the API */
 		var headers map[string]string
 		path := fmt.Sprintf("/config/v1/rules/%s", rs.Primary.ID)
-		_, err := client.send_request("ruler", "GET", path, "", headers)
+		_, err := client.send_request(context.Background(), "ruler", "GET", path, "", headers)
 		if err != nil {
 			return err
 		}
@@ -57,7 +58,7 @@ func testAccCheckMimirRuleGroupDestroy(s *terraform.State) error {
 
 		var headers map[string]string
 		path := fmt.Sprintf("/config/v1/rules/%s", rs.Primary.ID)
-		_, err := client.send_request("ruler", "GET", path, "", headers)
+		_, err := client.send_request(context.Background(), "ruler", "GET", path, "", headers)
 
 		// If the error is equivalent to 404 not found, the widget is destroyed.
 		// Otherwise return the error
@@ -1,11 +1,15 @@
 package mimir
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/common/model"
+	"io"
 	"net"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -20,6 +24,11 @@ func expandHTTPConfigOAuth2(v interface{}) *oauth2 {
 		oauth2Conf.TokenURL = cfg["token_url"].(string)
 		oauth2Conf.Scopes = expandStringArray(cfg["scopes"].([]interface{}))
 		oauth2Conf.EndpointParams = expandStringMap(cfg["endpoint_params"].(map[string]interface{}))
+		oauth2Conf.proxyConfig = expandProxyConfig(cfg)
+
+		if len(cfg["tls_config"].([]interface{})) > 0 {
+			oauth2Conf.TLSConfig = expandTLSConfig(cfg["tls_config"].([]interface{}))
+		}
 	}
 	return oauth2Conf
 }
@@ -32,15 +41,47 @@ func flattenHTTPConfigOAuth2(v *oauth2) []interface{} {
 		oauth2Conf["token_url"] = v.TokenURL
 		oauth2Conf["scopes"] = v.Scopes
 		oauth2Conf["endpoint_params"] = v.EndpointParams
+		flattenProxyConfig(v.proxyConfig, oauth2Conf)
+
+		if v.TLSConfig != nil {
+			oauth2Conf["tls_config"] = flattenTLSConfig(v.TLSConfig)
+		}
 	}
 	return []interface{}{oauth2Conf}
 }
 
+func expandProxyConfig(cfg map[string]interface{}) proxyConfig {
+	proxyConf := proxyConfig{
+		ProxyURL:             cfg["proxy_url"].(string),
+		NoProxy:              cfg["no_proxy"].(string),
+		ProxyFromEnvironment: cfg["proxy_from_environment"].(bool),
+	}
+	for k, v := range expandStringMap(cfg["proxy_connect_header"].(map[string]interface{})) {
+		if proxyConf.ProxyConnectHeader == nil {
+			proxyConf.ProxyConnectHeader = make(map[string][]string)
+		}
+		proxyConf.ProxyConnectHeader[k] = strings.Split(v, ",")
+	}
+	return proxyConf
+}
+
+func flattenProxyConfig(v proxyConfig, cfg map[string]interface{}) {
+	cfg["proxy_url"] = v.ProxyURL
+	cfg["no_proxy"] = v.NoProxy
+	cfg["proxy_from_environment"] = v.ProxyFromEnvironment
+
+	headers := make(map[string]interface{})
+	for k, values := range v.ProxyConnectHeader {
+		headers[k] = strings.Join(values, ",")
+	}
+	cfg["proxy_connect_header"] = headers
+}
+
 func expandHTTPConfigBasicAuth(v interface{}) *basicAuth {
 	var basicAuthConf *basicAuth
 	data := v.([]interface{})
 	if len(data) != 0 && data[0] != nil {
-		basicAuthConf := &basicAuth{}
+		basicAuthConf = &basicAuth{}
 		cfg := data[0].(map[string]interface{})
 		basicAuthConf.Username = cfg["username"].(string)
 		basicAuthConf.Password = cfg["password"].(string)
@@ -61,7 +102,7 @@ func expandHTTPConfigAuthorization(v interface{}) *authorization {
 	var authConf *authorization
 	data := v.([]interface{})
 	if len(data) != 0 && data[0] != nil {
-		authConf := &authorization{}
+		authConf = &authorization{}
 		cfg := data[0].(map[string]interface{})
 		authConf.Type = cfg["type"].(string)
 		authConf.Credentials = cfg["credentials"].(string)
@@ -84,8 +125,13 @@ func expandTLSConfig(v interface{}) *tlsConfig {
 	if len(data) != 0 && data[0] != nil {
 		tlsConf = &tlsConfig{}
 		cfg := data[0].(map[string]interface{})
+		tlsConf.CA = cfg["ca"].(string)
+		tlsConf.Cert = cfg["cert"].(string)
+		tlsConf.Key = cfg["key"].(string)
 		tlsConf.ServerName = cfg["server_name"].(string)
 		tlsConf.InsecureSkipVerify = cfg["insecure_skip_verify"].(bool)
+		tlsConf.MinVersion = cfg["min_version"].(string)
+		tlsConf.MaxVersion = cfg["max_version"].(string)
 	}
 	return tlsConf
 }
@@ -93,8 +139,13 @@ func expandTLSConfig(v interface{}) *tlsConfig {
 func flattenTLSConfig(v *tlsConfig) []interface{} {
 	tlsConf := make(map[string]interface{})
 	if v != nil {
+		tlsConf["ca"] = v.CA
+		tlsConf["cert"] = v.Cert
+		tlsConf["key"] = v.Key
 		tlsConf["server_name"] = v.ServerName
 		tlsConf["insecure_skip_verify"] = v.InsecureSkipVerify
+		tlsConf["min_version"] = v.MinVersion
+		tlsConf["max_version"] = v.MaxVersion
 	}
 	return []interface{}{tlsConf}
 }
@@ -105,9 +156,11 @@ func expandHTTPConfig(v interface{}) *httpClientConfig {
 	if len(data) != 0 && data[0] != nil {
 		httpConf = &httpClientConfig{}
 		cfg := data[0].(map[string]interface{})
-		httpConf.ProxyURL = cfg["proxy_url"].(string)
+		httpConf.proxyConfig = expandProxyConfig(cfg)
 		httpConf.FollowRedirects = new(bool)
 		*httpConf.FollowRedirects = cfg["follow_redirects"].(bool)
+		httpConf.EnableHTTP2 = new(bool)
+		*httpConf.EnableHTTP2 = cfg["enable_http2"].(bool)
 		httpConf.BearerToken = cfg["bearer_token"].(string)
 
 		if len(cfg["authorization"].([]interface{})) > 0 {
@@ -134,13 +187,17 @@ func flattenHTTPConfig(v *httpClientConfig) []interface{} {
 	httpConf := make(map[string]interface{})
 
 	if v != nil {
-		httpConf["proxy_url"] = v.ProxyURL
+		flattenProxyConfig(v.proxyConfig, httpConf)
 		httpConf["bearer_token"] = v.BearerToken
 
 		if v.FollowRedirects != nil {
 			httpConf["follow_redirects"] = v.FollowRedirects
 		}
 
+		if v.EnableHTTP2 != nil {
+			httpConf["enable_http2"] = v.EnableHTTP2
+		}
+
 		if v.BasicAuth != nil {
 			httpConf["basic_auth"] = flattenHTTPConfigBasicAuth(v.BasicAuth)
 		}
@@ -173,10 +230,7 @@ func expandGlobalConfig(v interface{}) *globalConfig {
 			globalConf.PagerdutyURL = &config.URL{pagerdutyURL}
 		}
 
-		slackAPIURL, _ := url.Parse(cfg["slack_api_url"].(string))
-		if slackAPIURL.String() != "" {
-			globalConf.SlackAPIURL = &config.URL{slackAPIURL}
-		}
+		globalConf.SlackAPIURL = cfg["slack_api_url"].(string)
 
 		globalConf.SMTPFrom = cfg["smtp_from"].(string)
 		globalConf.SMTPHello = cfg["smtp_hello"].(string)
@@ -216,6 +270,23 @@ func expandGlobalConfig(v interface{}) *globalConfig {
 		if telegramAPIURL.String() != "" {
 			globalConf.TelegramAPIURL = &config.URL{telegramAPIURL}
 		}
+
+		jiraAPIURL, _ := url.Parse(cfg["jira_api_url"].(string))
+		if jiraAPIURL.String() != "" {
+			globalConf.JiraAPIURL = &config.URL{URL: jiraAPIURL}
+		}
+
+		webexAPIURL, _ := url.Parse(cfg["webex_api_url"].(string))
+		if webexAPIURL.String() != "" {
+			globalConf.WebexAPIURL = &config.URL{URL: webexAPIURL}
+		}
+
+		globalConf.RocketchatToken = cfg["rocketchat_token"].(string)
+		globalConf.RocketchatTokenID = cfg["rocketchat_token_id"].(string)
+		rocketchatAPIURL, _ := url.Parse(cfg["rocketchat_api_url"].(string))
+		if rocketchatAPIURL.String() != "" {
+			globalConf.RocketchatAPIURL = &config.URL{URL: rocketchatAPIURL}
+		}
 	}
 	return globalConf
 }
@@ -232,10 +303,6 @@ func flattenGlobalConfig(v *globalConfig) []interface{} {
 			globalConf["pagerduty_url"] = v.PagerdutyURL.URL.String()
 		}
 
-		if v.SlackAPIURL != nil {
-			globalConf["slack_api_url"] = v.SlackAPIURL.URL.String()
-		}
-
 		if v.OpsGenieAPIURL != nil {
 			globalConf["opsgenie_api_url"] = v.OpsGenieAPIURL.URL.String()
 		}
@@ -251,10 +318,25 @@ func flattenGlobalConfig(v *globalConfig) []interface{} {
 			globalConf["telegram_api_url"] = v.TelegramAPIURL.URL.String()
 		}
 
+		if v.JiraAPIURL != nil {
+			globalConf["jira_api_url"] = v.JiraAPIURL.URL.String()
+		}
+
+		if v.WebexAPIURL != nil {
+			globalConf["webex_api_url"] = v.WebexAPIURL.URL.String()
+		}
+
+		if v.RocketchatAPIURL != nil {
+			globalConf["rocketchat_api_url"] = v.RocketchatAPIURL.URL.String()
+		}
+
+		globalConf["slack_api_url"] = v.SlackAPIURL
 		globalConf["opsgenie_api_key"] = v.OpsGenieAPIKey
 		globalConf["wechat_api_secret"] = v.WeChatAPISecret
 		globalConf["wechat_api_corp_id"] = v.WeChatAPICorpID
 		globalConf["victorops_api_key"] = v.VictorOpsAPIKey
+		globalConf["rocketchat_token"] = v.RocketchatToken
+		globalConf["rocketchat_token_id"] = v.RocketchatTokenID
 
 		if v.HTTPConfig != nil {
 			globalConf["http_config"] = flattenHTTPConfig(v.HTTPConfig)
@@ -315,16 +397,31 @@ func expandReceiverConfig(v []interface{}) []*receiver {
 		if raw, ok := data["sns_configs"]; ok {
 			cfg.SNSConfigs = expandSnsConfig(raw.([]interface{}))
 		}
+		if raw, ok := data["discord_configs"]; ok {
+			cfg.DiscordConfigs = expandDiscordConfig(raw.([]interface{}))
+		}
+		if raw, ok := data["msteams_configs"]; ok {
+			cfg.MSTeamsConfigs = expandMSTeamsConfig(raw.([]interface{}))
+		}
+		if raw, ok := data["webex_configs"]; ok {
+			cfg.WebexConfigs = expandWebexConfig(raw.([]interface{}))
+		}
+		if raw, ok := data["rocketchat_configs"]; ok {
+			cfg.RocketchatConfigs = expandRocketchatConfig(raw.([]interface{}))
+		}
+		if raw, ok := data["jira_configs"]; ok {
+			cfg.JiraConfigs = expandJiraConfig(raw.([]interface{}))
+		}
 		receiverConf = append(receiverConf, cfg)
 	}
 	return receiverConf
 }
 
-func flattenReceiverConfig(v []*receiver) []interface{} {
+func flattenReceiverConfig(v []*receiver) ([]interface{}, error) {
 	var receiverConf []interface{}
 
 	if v == nil {
-		return receiverConf
+		return receiverConf, nil
 	}
 
 	for _, v := range v {
@@ -340,9 +437,18 @@ func flattenReceiverConfig(v []*receiver) []interface{} {
 		cfg["telegram_configs"] = flattenTelegramConfig(v.TelegramConfigs)
 		cfg["victorops_configs"] = flattenVictorOpsConfig(v.VictorOpsConfigs)
 		cfg["sns_configs"] = flattenSnsConfig(v.SNSConfigs)
+		cfg["discord_configs"] = flattenDiscordConfig(v.DiscordConfigs)
+		cfg["msteams_configs"] = flattenMSTeamsConfig(v.MSTeamsConfigs)
+		cfg["webex_configs"] = flattenWebexConfig(v.WebexConfigs)
+		cfg["rocketchat_configs"] = flattenRocketchatConfig(v.RocketchatConfigs)
+		jiraConf, err := flattenJiraConfig(v.JiraConfigs)
+		if err != nil {
+			return nil, fmt.Errorf("receiver %q: %v", v.Name, err)
+		}
+		cfg["jira_configs"] = jiraConf
 		receiverConf = append(receiverConf, cfg)
 	}
-	return receiverConf
+	return receiverConf, nil
 }
 
 func expandSnsSigV4Config(v interface{}) sigV4Config {
@@ -570,6 +676,506 @@ func flattenTelegramConfig(v []*telegramConfig) []interface{} {
 	return telegramConf
 }
 
+func expandDiscordConfig(v []interface{}) []*discordConfig {
+	var discordConf []*discordConfig
+
+	for _, v := range v {
+		cfg := &discordConfig{}
+		data := v.(map[string]interface{})
+
+		if raw, ok := data["send_resolved"]; ok {
+			cfg.VSendResolved = new(bool)
+			*cfg.VSendResolved = raw.(bool)
+		}
+		if raw, ok := data["http_config"]; ok {
+			cfg.HTTPConfig = expandHTTPConfig(raw.(interface{}))
+		}
+		if raw, ok := data["webhook_url"]; ok {
+			cfg.WebhookURL = raw.(string)
+		}
+		if raw, ok := data["title"]; ok {
+			cfg.Title = raw.(string)
+		}
+		if raw, ok := data["message"]; ok {
+			cfg.Message = raw.(string)
+		}
+
+		discordConf = append(discordConf, cfg)
+	}
+	return discordConf
+}
+
+func flattenDiscordConfig(v []*discordConfig) []interface{} {
+	var discordConf []interface{}
+
+	if v == nil {
+		return discordConf
+	}
+
+	for _, v := range v {
+		cfg := make(map[string]interface{})
+		cfg["send_resolved"] = v.VSendResolved
+		if v.HTTPConfig != nil {
+			cfg["http_config"] = flattenHTTPConfig(v.HTTPConfig)
+		}
+		cfg["webhook_url"] = v.WebhookURL
+		cfg["title"] = v.Title
+		cfg["message"] = v.Message
+		discordConf = append(discordConf, cfg)
+	}
+	return discordConf
+}
+
+func expandMSTeamsConfig(v []interface{}) []*msTeamsConfig {
+	var msTeamsConf []*msTeamsConfig
+
+	for _, v := range v {
+		cfg := &msTeamsConfig{}
+		data := v.(map[string]interface{})
+
+		if raw, ok := data["send_resolved"]; ok {
+			cfg.VSendResolved = new(bool)
+			*cfg.VSendResolved = raw.(bool)
+		}
+		if raw, ok := data["http_config"]; ok {
+			cfg.HTTPConfig = expandHTTPConfig(raw.(interface{}))
+		}
+		if raw, ok := data["webhook_url"]; ok {
+			cfg.WebhookURL = raw.(string)
+		}
+		if raw, ok := data["title"]; ok {
+			cfg.Title = raw.(string)
+		}
+		if raw, ok := data["summary"]; ok {
+			cfg.Summary = raw.(string)
+		}
+		if raw, ok := data["text"]; ok {
+			cfg.Text = raw.(string)
+		}
+
+		msTeamsConf = append(msTeamsConf, cfg)
+	}
+	return msTeamsConf
+}
+
+func flattenMSTeamsConfig(v []*msTeamsConfig) []interface{} {
+	var msTeamsConf []interface{}
+
+	if v == nil {
+		return msTeamsConf
+	}
+
+	for _, v := range v {
+		cfg := make(map[string]interface{})
+		cfg["send_resolved"] = v.VSendResolved
+		if v.HTTPConfig != nil {
+			cfg["http_config"] = flattenHTTPConfig(v.HTTPConfig)
+		}
+		cfg["webhook_url"] = v.WebhookURL
+		cfg["title"] = v.Title
+		cfg["summary"] = v.Summary
+		cfg["text"] = v.Text
+		msTeamsConf = append(msTeamsConf, cfg)
+	}
+	return msTeamsConf
+}
+
+func expandWebexConfig(v []interface{}) []*webexConfig {
+	var webexConf []*webexConfig
+
+	for _, v := range v {
+		cfg := &webexConfig{}
+		data := v.(map[string]interface{})
+
+		if raw, ok := data["send_resolved"]; ok {
+			cfg.VSendResolved = new(bool)
+			*cfg.VSendResolved = raw.(bool)
+		}
+		if raw, ok := data["http_config"]; ok {
+			cfg.HTTPConfig = expandHTTPConfig(raw.(interface{}))
+		}
+		if raw, ok := data["api_url"]; ok {
+			cfg.APIURL = raw.(string)
+		}
+		if raw, ok := data["room_id"]; ok {
+			cfg.RoomID = raw.(string)
+		}
+		if raw, ok := data["message"]; ok {
+			cfg.Message = raw.(string)
+		}
+
+		webexConf = append(webexConf, cfg)
+	}
+	return webexConf
+}
+
+func flattenWebexConfig(v []*webexConfig) []interface{} {
+	var webexConf []interface{}
+
+	if v == nil {
+		return webexConf
+	}
+
+	for _, v := range v {
+		cfg := make(map[string]interface{})
+		cfg["send_resolved"] = v.VSendResolved
+		if v.HTTPConfig != nil {
+			cfg["http_config"] = flattenHTTPConfig(v.HTTPConfig)
+		}
+		cfg["api_url"] = v.APIURL
+		cfg["room_id"] = v.RoomID
+		cfg["message"] = v.Message
+		webexConf = append(webexConf, cfg)
+	}
+	return webexConf
+}
+
+func expandRocketchatConfig(v []interface{}) []*rocketchatConfig {
+	var rocketchatConf []*rocketchatConfig
+
+	for _, v := range v {
+		cfg := &rocketchatConfig{}
+		data := v.(map[string]interface{})
+
+		if raw, ok := data["send_resolved"]; ok {
+			cfg.VSendResolved = new(bool)
+			*cfg.VSendResolved = raw.(bool)
+		}
+		if raw, ok := data["http_config"]; ok {
+			cfg.HTTPConfig = expandHTTPConfig(raw.(interface{}))
+		}
+		if raw, ok := data["api_url"]; ok {
+			cfg.APIURL = raw.(string)
+		}
+		if raw, ok := data["token"]; ok {
+			cfg.Token = raw.(string)
+		}
+		if raw, ok := data["token_id"]; ok {
+			cfg.TokenID = raw.(string)
+		}
+		if raw, ok := data["channel"]; ok {
+			cfg.Channel = raw.(string)
+		}
+		if raw, ok := data["color"]; ok {
+			cfg.Color = raw.(string)
+		}
+		if raw, ok := data["title"]; ok {
+			cfg.Title = raw.(string)
+		}
+		if raw, ok := data["title_link"]; ok {
+			cfg.TitleLink = raw.(string)
+		}
+		if raw, ok := data["text"]; ok {
+			cfg.Text = raw.(string)
+		}
+		if raw, ok := data["fields"]; ok {
+			cfg.Fields = expandRocketchatConfigFields(raw.([]interface{}))
+		}
+		if raw, ok := data["short_fields"]; ok {
+			cfg.ShortFields = raw.(bool)
+		}
+		if raw, ok := data["emoji"]; ok {
+			cfg.Emoji = raw.(string)
+		}
+		if raw, ok := data["icon_url"]; ok {
+			cfg.IconURL = raw.(string)
+		}
+		if raw, ok := data["image_url"]; ok {
+			cfg.ImageURL = raw.(string)
+		}
+		if raw, ok := data["thumb_url"]; ok {
+			cfg.ThumbURL = raw.(string)
+		}
+		if raw, ok := data["link_names"]; ok {
+			cfg.LinkNames = raw.(bool)
+		}
+		if raw, ok := data["actions"]; ok {
+			cfg.Actions = expandRocketchatConfigActions(raw.([]interface{}))
+		}
+
+		rocketchatConf = append(rocketchatConf, cfg)
+	}
+	return rocketchatConf
+}
+
+func flattenRocketchatConfig(v []*rocketchatConfig) []interface{} {
+	var rocketchatConf []interface{}
+
+	if v == nil {
+		return rocketchatConf
+	}
+
+	for _, v := range v {
+		cfg := make(map[string]interface{})
+		cfg["send_resolved"] = v.VSendResolved
+		if v.HTTPConfig != nil {
+			cfg["http_config"] = flattenHTTPConfig(v.HTTPConfig)
+		}
+		cfg["api_url"] = v.APIURL
+		cfg["token"] = v.Token
+		cfg["token_id"] = v.TokenID
+		cfg["channel"] = v.Channel
+		cfg["color"] = v.Color
+		cfg["title"] = v.Title
+		cfg["title_link"] = v.TitleLink
+		cfg["text"] = v.Text
+		cfg["fields"] = flattenRocketchatConfigFields(v.Fields)
+		cfg["short_fields"] = v.ShortFields
+		cfg["emoji"] = v.Emoji
+		cfg["icon_url"] = v.IconURL
+		cfg["image_url"] = v.ImageURL
+		cfg["thumb_url"] = v.ThumbURL
+		cfg["link_names"] = v.LinkNames
+		cfg["actions"] = flattenRocketchatConfigActions(v.Actions)
+		rocketchatConf = append(rocketchatConf, cfg)
+	}
+	return rocketchatConf
+}
+
+func expandRocketchatConfigFields(v []interface{}) []rocketchatField {
+	var rocketchatFieldConf []rocketchatField
+
+	for _, v := range v {
+		var cfg rocketchatField
+		data := v.(map[string]interface{})
+
+		if raw, ok := data["title"]; ok {
+			cfg.Title = raw.(string)
+		}
+		if raw, ok := data["value"]; ok {
+			cfg.Value = raw.(string)
+		}
+		if raw, ok := data["short"]; ok {
+			cfg.Short = raw.(bool)
+		}
+		rocketchatFieldConf = append(rocketchatFieldConf, cfg)
+	}
+	return rocketchatFieldConf
+}
+
+func flattenRocketchatConfigFields(v []rocketchatField) []interface{} {
+	var rocketchatFieldConf []interface{}
+
+	if v == nil {
+		return rocketchatFieldConf
+	}
+
+	for _, v := range v {
+		cfg := make(map[string]interface{})
+		cfg["title"] = v.Title
+		cfg["value"] = v.Value
+		cfg["short"] = v.Short
+		rocketchatFieldConf = append(rocketchatFieldConf, cfg)
+	}
+	return rocketchatFieldConf
+}
+
+func expandRocketchatConfigActions(v []interface{}) []rocketchatAction {
+	var rocketchatActionConf []rocketchatAction
+
+	for _, v := range v {
+		var cfg rocketchatAction
+		data := v.(map[string]interface{})
+
+		if raw, ok := data["type"]; ok {
+			cfg.Type = raw.(string)
+		}
+		if raw, ok := data["text"]; ok {
+			cfg.Text = raw.(string)
+		}
+		if raw, ok := data["url"]; ok {
+			cfg.URL = raw.(string)
+		}
+		if raw, ok := data["msg"]; ok {
+			cfg.Msg = raw.(string)
+		}
+		rocketchatActionConf = append(rocketchatActionConf, cfg)
+	}
+	return rocketchatActionConf
+}
+
+func flattenRocketchatConfigActions(v []rocketchatAction) []interface{} {
+	var rocketchatActionConf []interface{}
+
+	if v == nil {
+		return rocketchatActionConf
+	}
+
+	for _, v := range v {
+		cfg := make(map[string]interface{})
+		cfg["type"] = v.Type
+		cfg["text"] = v.Text
+		cfg["url"] = v.URL
+		cfg["msg"] = v.Msg
+		rocketchatActionConf = append(rocketchatActionConf, cfg)
+	}
+	return rocketchatActionConf
+}
+
+func expandJiraConfig(v []interface{}) []*jiraConfig {
+	var jiraConf []*jiraConfig
+
+	for _, v := range v {
+		cfg := &jiraConfig{}
+		data := v.(map[string]interface{})
+
+		if raw, ok := data["send_resolved"]; ok {
+			cfg.VSendResolved = new(bool)
+			*cfg.VSendResolved = raw.(bool)
+		}
+		if raw, ok := data["http_config"]; ok {
+			cfg.HTTPConfig = expandHTTPConfig(raw.(interface{}))
+		}
+		if raw, ok := data["api_url"]; ok {
+			cfg.APIURL = raw.(string)
+		}
+		if raw, ok := data["project"]; ok {
+			cfg.Project = raw.(string)
+		}
+		if raw, ok := data["issue_type"]; ok {
+			cfg.IssueType = raw.(string)
+		}
+		if raw, ok := data["summary"]; ok {
+			cfg.Summary = raw.(string)
+		}
+		if raw, ok := data["description"]; ok {
+			cfg.Description = raw.(string)
+		}
+		if raw, ok := data["priority"]; ok {
+			cfg.Priority = raw.(string)
+		}
+		if raw, ok := data["labels"]; ok {
+			cfg.Labels = expandStringArray(raw.([]interface{}))
+		}
+		if raw, ok := data["fields"]; ok {
+			cfg.Fields = expandJiraConfigFields(raw.(map[string]interface{}))
+		}
+		if raw, ok := data["reopen_transition"]; ok {
+			cfg.ReopenTransition = raw.(string)
+		}
+		if raw, ok := data["resolve_transition"]; ok {
+			cfg.ResolveTransition = raw.(string)
+		}
+		if raw, ok := data["wont_fix_resolution"]; ok {
+			cfg.WontFixResolution = raw.(string)
+		}
+		if raw, ok := data["reopen_duration"]; ok {
+			cfg.ReopenDuration = raw.(string)
+		}
+
+		jiraConf = append(jiraConf, cfg)
+	}
+	return jiraConf
+}
+
+func flattenJiraConfig(v []*jiraConfig) ([]interface{}, error) {
+	var jiraConf []interface{}
+
+	if v == nil {
+		return jiraConf, nil
+	}
+
+	for _, v := range v {
+		cfg := make(map[string]interface{})
+		cfg["send_resolved"] = v.VSendResolved
+		if v.HTTPConfig != nil {
+			cfg["http_config"] = flattenHTTPConfig(v.HTTPConfig)
+		}
+		cfg["api_url"] = v.APIURL
+		cfg["project"] = v.Project
+		cfg["issue_type"] = v.IssueType
+		cfg["summary"] = v.Summary
+		cfg["description"] = v.Description
+		cfg["priority"] = v.Priority
+		cfg["labels"] = v.Labels
+		fields, err := flattenJiraConfigFields(v.Fields)
+		if err != nil {
+			return nil, err
+		}
+		cfg["fields"] = fields
+		cfg["reopen_transition"] = v.ReopenTransition
+		cfg["resolve_transition"] = v.ResolveTransition
+		cfg["wont_fix_resolution"] = v.WontFixResolution
+		cfg["reopen_duration"] = v.ReopenDuration
+		jiraConf = append(jiraConf, cfg)
+	}
+	return jiraConf, nil
+}
+
+// expandJiraConfigFields decodes the JSON encoded values of the custom
+// fields, e.g. `{"value": "foo"}` for a select list, and keeps the other
+// values as strings. Numbers are decoded as integers when they fit, so that
+// large field IDs keep their precision.
+func expandJiraConfigFields(v map[string]interface{}) map[string]interface{} {
+	fields := make(map[string]interface{})
+	for k, raw := range v {
+		if value, err := decodeJiraConfigField(raw.(string)); err == nil {
+			fields[k] = value
+		} else {
+			fields[k] = raw.(string)
+		}
+	}
+	return fields
+}
+
+func decodeJiraConfigField(s string) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return convertJSONNumbers(value), nil
+}
+
+// convertJSONNumbers replaces the json.Number values, which would be
+// marshaled as YAML strings, by int64 or float64 values.
+func convertJSONNumbers(v interface{}) interface{} {
+	switch value := v.(type) {
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return i
+		}
+		if f, err := value.Float64(); err == nil {
+			return f
+		}
+		return value.String()
+	case map[string]interface{}:
+		for k, item := range value {
+			value[k] = convertJSONNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = convertJSONNumbers(item)
+		}
+	}
+	return v
+}
+
+func flattenJiraConfigFields(v map[string]interface{}) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	for k, value := range v {
+		// Strings which would be decoded as something else are encoded too,
+		// so that they are sent as strings again.
+		if s, ok := value.(string); ok {
+			if _, err := decodeJiraConfigField(s); err != nil {
+				fields[k] = s
+				continue
+			}
+		}
+		out, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot encode jira field %q: %v", k, err)
+		}
+		fields[k] = string(out)
+	}
+	return fields, nil
+}
+
 func expandOpsgenieResponder(v []interface{}) []opsgenieResponder {
 	var opsgenieResponderConf []opsgenieResponder
 
@@ -1530,11 +2136,11 @@ func flattenMuteTimeIntervalConfig(v []*muteTimeInterval) []interface{} {
 	return muteTimeIntervalConf
 }
 
-func expandTimeIntervalConfig(v []interface{}) []timeinterval.TimeInterval {
-	var timeIntervalConf []timeinterval.TimeInterval
+func expandTimeIntervalConfig(v []interface{}) []timeInterval {
+	var timeIntervalConf []timeInterval
 
 	for _, v := range v {
-		var cfg timeinterval.TimeInterval
+		var cfg timeInterval
 		data := v.(map[string]interface{})
 
 		if raw, ok := data["times"]; ok {
@@ -1552,6 +2158,9 @@ func expandTimeIntervalConfig(v []interface{}) []timeinterval.TimeInterval {
 		if raw, ok := data["years"]; ok {
 			cfg.Years = expandYearRange(raw.([]interface{}))
 		}
+		if raw, ok := data["location"]; ok {
+			cfg.Location = raw.(string)
+		}
 		timeIntervalConf = append(timeIntervalConf, cfg)
 	}
 	return timeIntervalConf
@@ -1732,7 +2341,7 @@ func flattenYearRange(v []timeinterval.YearRange) []interface{} {
 	return inclusiveRangeConf
 }
 
-func flattenTimeIntervalConfig(v []timeinterval.TimeInterval) []interface{} {
+func flattenTimeIntervalConfig(v []timeInterval) []interface{} {
 	var timeIntervalConf []interface{}
 
 	if v == nil {
@@ -1746,6 +2355,7 @@ func flattenTimeIntervalConfig(v []timeinterval.TimeInterval) []interface{} {
 		cfg["days_of_month"] = flattenDayOfMonthRange(v.DaysOfMonth)
 		cfg["months"] = flattenMonthRange(v.Months)
 		cfg["years"] = flattenYearRange(v.Years)
+		cfg["location"] = v.Location
 		timeIntervalConf = append(timeIntervalConf, cfg)
 	}
 	return timeIntervalConf
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/common/model"
 )
 
@@ -24,6 +25,7 @@ type alertmanagerConfig struct {
 	InhibitRules      []*inhibitRule      `yaml:"inhibit_rules,omitempty" json:"inhibit_rules,omitempty"`
 	Receivers         []*receiver         `yaml:"receivers,omitempty" json:"receivers,omitempty"`
 	MuteTimeIntervals []*muteTimeInterval `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty"`
+	TimeIntervals     []*muteTimeInterval `yaml:"time_intervals,omitempty" json:"time_intervals,omitempty"`
 	Templates         []string            `yaml:"templates,omitempty" json:"templates,omitempty"`
 }
 
@@ -38,20 +40,26 @@ type globalConfig struct {
 	SMTPHello        string          `yaml:"smtp_hello,omitempty" json:"smtp_hello,omitempty"`
 	SMTPSmarthost    config.HostPort `yaml:"smtp_smarthost,omitempty" json:"smtp_smarthost,omitempty"`
 	SMTPAuthUsername string          `yaml:"smtp_auth_username,omitempty" json:"smtp_auth_username,omitempty"`
-	SMTPAuthPassword string          `yaml:"smtp_auth_password,omitempty" json:"smtp_auth_password,omitempty"`
-	SMTPAuthSecret   string          `yaml:"smtp_auth_secret,omitempty" json:"smtp_auth_secret,omitempty"`
+	SMTPAuthPassword string          `yaml:"smtp_auth_password,omitempty" json:"smtp_auth_password,omitempty" secret:"true"`
+	SMTPAuthSecret   string          `yaml:"smtp_auth_secret,omitempty" json:"smtp_auth_secret,omitempty" secret:"true"`
 	SMTPAuthIdentity string          `yaml:"smtp_auth_identity,omitempty" json:"smtp_auth_identity,omitempty"`
 	SMTPRequireTLS   *bool           `yaml:"smtp_require_tls,omitempty" json:"smtp_require_tls,omitempty"`
-	SlackAPIURL      *config.URL     `yaml:"slack_api_url,omitempty" json:"slack_api_url,omitempty"`
+	SlackAPIURL      string          `yaml:"slack_api_url,omitempty" json:"slack_api_url,omitempty" secret:"true"`
 	PagerdutyURL     *config.URL     `yaml:"pagerduty_url,omitempty" json:"pagerduty_url,omitempty"`
 	OpsGenieAPIURL   *config.URL     `yaml:"opsgenie_api_url,omitempty" json:"opsgenie_api_url,omitempty"`
-	OpsGenieAPIKey   string          `yaml:"opsgenie_api_key,omitempty" json:"opsgenie_api_key,omitempty"`
+	OpsGenieAPIKey   string          `yaml:"opsgenie_api_key,omitempty" json:"opsgenie_api_key,omitempty" secret:"true"`
 	WeChatAPIURL     *config.URL     `yaml:"wechat_api_url,omitempty" json:"wechat_api_url,omitempty"`
-	WeChatAPISecret  string          `yaml:"wechat_api_secret,omitempty" json:"wechat_api_secret,omitempty"`
+	WeChatAPISecret  string          `yaml:"wechat_api_secret,omitempty" json:"wechat_api_secret,omitempty" secret:"true"`
 	WeChatAPICorpID  string          `yaml:"wechat_api_corp_id,omitempty" json:"wechat_api_corp_id,omitempty"`
 	VictorOpsAPIURL  *config.URL     `yaml:"victorops_api_url,omitempty" json:"victorops_api_url,omitempty"`
-	VictorOpsAPIKey  string          `yaml:"victorops_api_key,omitempty" json:"victorops_api_key,omitempty"`
+	VictorOpsAPIKey  string          `yaml:"victorops_api_key,omitempty" json:"victorops_api_key,omitempty" secret:"true"`
 	TelegramAPIURL   *config.URL     `yaml:"telegram_api_url,omitempty" json:"telegram_api_url,omitempty"`
+	WebexAPIURL      *config.URL     `yaml:"webex_api_url,omitempty" json:"webex_api_url,omitempty"`
+	JiraAPIURL       *config.URL     `yaml:"jira_api_url,omitempty" json:"jira_api_url,omitempty"`
+
+	RocketchatAPIURL  *config.URL `yaml:"rocketchat_api_url,omitempty" json:"rocketchat_api_url,omitempty"`
+	RocketchatToken   string      `yaml:"rocketchat_token,omitempty" json:"rocketchat_token,omitempty" secret:"true"`
+	RocketchatTokenID string      `yaml:"rocketchat_token_id,omitempty" json:"rocketchat_token_id,omitempty" secret:"true"`
 }
 
 type route struct {
@@ -74,22 +82,27 @@ type inhibitRule struct {
 }
 
 type receiver struct {
-	Name             string             `yaml:"name" json:"name"`
-	OpsgenieConfigs  []*opsgenieConfig  `yaml:"opsgenie_configs,omitempty" json:"opsgenie_configs,omitempty"`
-	PagerdutyConfigs []*pagerdutyConfig `yaml:"pagerduty_configs,omitempty" json:"pagerduty_configs,omitempty"`
-	SlackConfigs     []*slackConfig     `yaml:"slack_configs,omitempty" json:"slack_configs,omitempty"`
-	WebhookConfigs   []*webhookConfig   `yaml:"webhook_configs,omitempty" json:"webhook_configs,omitempty"`
-	WeChatConfigs    []*weChatConfig    `yaml:"wechat_configs,omitempty" json:"wechat_config,omitempty"`
-	EmailConfigs     []*emailConfig     `yaml:"email_configs,omitempty" json:"email_configs,omitempty"`
-	PushoverConfigs  []*pushoverConfig  `yaml:"pushover_configs,omitempty" json:"pushover_configs,omitempty"`
-	VictorOpsConfigs []*victorOpsConfig `yaml:"victorops_configs,omitempty" json:"victorops_configs,omitempty"`
-	SNSConfigs       []*snsConfig       `yaml:"sns_configs,omitempty" json:"sns_configs,omitempty"`
-	TelegramConfigs  []*telegramConfig  `yaml:"telegram_configs,omitempty" json:"telegram_configs,omitempty"`
+	Name              string              `yaml:"name" json:"name"`
+	OpsgenieConfigs   []*opsgenieConfig   `yaml:"opsgenie_configs,omitempty" json:"opsgenie_configs,omitempty"`
+	PagerdutyConfigs  []*pagerdutyConfig  `yaml:"pagerduty_configs,omitempty" json:"pagerduty_configs,omitempty"`
+	SlackConfigs      []*slackConfig      `yaml:"slack_configs,omitempty" json:"slack_configs,omitempty"`
+	WebhookConfigs    []*webhookConfig    `yaml:"webhook_configs,omitempty" json:"webhook_configs,omitempty"`
+	WeChatConfigs     []*weChatConfig     `yaml:"wechat_configs,omitempty" json:"wechat_config,omitempty"`
+	EmailConfigs      []*emailConfig      `yaml:"email_configs,omitempty" json:"email_configs,omitempty"`
+	PushoverConfigs   []*pushoverConfig   `yaml:"pushover_configs,omitempty" json:"pushover_configs,omitempty"`
+	VictorOpsConfigs  []*victorOpsConfig  `yaml:"victorops_configs,omitempty" json:"victorops_configs,omitempty"`
+	SNSConfigs        []*snsConfig        `yaml:"sns_configs,omitempty" json:"sns_configs,omitempty"`
+	TelegramConfigs   []*telegramConfig   `yaml:"telegram_configs,omitempty" json:"telegram_configs,omitempty"`
+	DiscordConfigs    []*discordConfig    `yaml:"discord_configs,omitempty" json:"discord_configs,omitempty"`
+	MSTeamsConfigs    []*msTeamsConfig    `yaml:"msteams_configs,omitempty" json:"msteams_configs,omitempty"`
+	WebexConfigs      []*webexConfig      `yaml:"webex_configs,omitempty" json:"webex_configs,omitempty"`
+	RocketchatConfigs []*rocketchatConfig `yaml:"rocketchat_configs,omitempty" json:"rocketchat_configs,omitempty"`
+	JiraConfigs       []*jiraConfig       `yaml:"jira_configs,omitempty" json:"jira_configs,omitempty"`
 }
 
 type webhookConfig struct {
 	VSendResolved *bool             `yaml:"send_resolved,omitempty" json:"send_resolved,omitempty"`
-	URL           string            `yaml:"url,omitempty" json:"url,omitempty"`
+	URL           string            `yaml:"url,omitempty" json:"url,omitempty" secret:"true"`
 	HTTPConfig    *httpClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
 	MaxAlerts     int32             `yaml:"max_alerts,omitempty" json:"max_alerts,omitempty"`
 }
@@ -97,8 +110,8 @@ type webhookConfig struct {
 type pagerdutyConfig struct {
 	VSendResolved *bool             `yaml:"send_resolved,omitempty" json:"send_resolved,omitempty"`
 	HTTPConfig    *httpClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
-	ServiceKey    string            `yaml:"service_key,omitempty" json:"service_key,omitempty"`
-	RoutingKey    string            `yaml:"routing_key,omitempty" json:"routing_key,omitempty"`
+	ServiceKey    string            `yaml:"service_key,omitempty" json:"service_key,omitempty" secret:"true"`
+	RoutingKey    string            `yaml:"routing_key,omitempty" json:"routing_key,omitempty" secret:"true"`
 	URL           string            `yaml:"url,omitempty" json:"url,omitempty"`
 	Client        string            `yaml:"client,omitempty" json:"client,omitempty"`
 	ClientURL     string            `yaml:"client_url,omitempty" json:"client_url,omitempty"`
@@ -115,7 +128,7 @@ type pagerdutyConfig struct {
 type opsgenieConfig struct {
 	VSendResolved *bool               `yaml:"send_resolved,omitempty" json:"send_resolved,omitempty"`
 	HTTPConfig    *httpClientConfig   `yaml:"http_config,omitempty" json:"http_config,omitempty"`
-	APIKey        string              `yaml:"api_key,omitempty" json:"api_key,omitempty"`
+	APIKey        string              `yaml:"api_key,omitempty" json:"api_key,omitempty" secret:"true"`
 	APIURL        string              `yaml:"api_url,omitempty" json:"api_url,omitempty"`
 	Message       string              `yaml:"message,omitempty" json:"message,omitempty"`
 	Description   string              `yaml:"description,omitempty" json:"description,omitempty"`
@@ -132,7 +145,7 @@ type opsgenieConfig struct {
 
 type weChatConfig struct {
 	VSendResolved *bool             `yaml:"send_resolved,omitempty" json:"send_resolved,omitempty"`
-	APISecret     string            `yaml:"api_secret,omitempty" json:"api_secret,omitempty"`
+	APISecret     string            `yaml:"api_secret,omitempty" json:"api_secret,omitempty" secret:"true"`
 	APIURL        string            `yaml:"api_url,omitempty" json:"api_url,omitempty"`
 	CorpID        string            `yaml:"corp_id,omitempty" json:"corp_id,omitempty"`
 	AgentID       string            `yaml:"agent_id,omitempty" json:"agent_id,omitempty"`
@@ -147,7 +160,7 @@ type weChatConfig struct {
 type slackConfig struct {
 	VSendResolved *bool             `yaml:"send_resolved,omitempty" json:"send_resolved,omitempty"`
 	HTTPConfig    *httpClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
-	APIURL        string            `yaml:"api_url,omitempty" json:"api_url,omitempty"`
+	APIURL        string            `yaml:"api_url,omitempty" json:"api_url,omitempty" secret:"true"`
 	Channel       string            `yaml:"channel,omitempty" json:"channel,omitempty"`
 	Username      string            `yaml:"username,omitempty" json:"username,omitempty"`
 	Color         string            `yaml:"color,omitempty" json:"color,omitempty"`
@@ -173,35 +186,51 @@ type httpClientConfig struct {
 	Authorization   *authorization `yaml:"authorization,omitempty"`
 	BasicAuth       *basicAuth     `yaml:"basic_auth,omitempty"`
 	OAuth2          *oauth2        `yaml:"oauth2,omitempty"`
-	BearerToken     string         `yaml:"bearer_token,omitempty"`
-	ProxyURL        string         `yaml:"proxy_url,omitempty"`
+	BearerToken     string         `yaml:"bearer_token,omitempty" secret:"true"`
 	TLSConfig       *tlsConfig     `yaml:"tls_config,omitempty"`
 	FollowRedirects *bool          `yaml:"follow_redirects,omitempty"`
+	EnableHTTP2     *bool          `yaml:"enable_http2,omitempty"`
+
+	proxyConfig `yaml:",inline"`
+}
+
+type proxyConfig struct {
+	ProxyURL             string              `yaml:"proxy_url,omitempty"`
+	NoProxy              string              `yaml:"no_proxy,omitempty"`
+	ProxyFromEnvironment bool                `yaml:"proxy_from_environment,omitempty"`
+	ProxyConnectHeader   map[string][]string `yaml:"proxy_connect_header,omitempty" secret:"true"`
 }
 
 type tlsConfig struct {
+	CA                 string `yaml:"ca,omitempty"`
+	Cert               string `yaml:"cert,omitempty"`
+	Key                string `yaml:"key,omitempty" secret:"true"`
 	ServerName         string `yaml:"server_name,omitempty"`
 	InsecureSkipVerify bool   `yaml:"insecure_skip_verify,omitempty"`
+	MinVersion         string `yaml:"min_version,omitempty"`
+	MaxVersion         string `yaml:"max_version,omitempty"`
 }
 
 type authorization struct {
 	Type        string `yaml:"type,omitempty"`
-	Credentials string `yaml:"credentials,omitempty"`
+	Credentials string `yaml:"credentials,omitempty" secret:"true"`
 }
 
 type basicAuth struct {
 	Username string `yaml:"username,omitempty"`
-	Password string `yaml:"password,omitempty"`
+	Password string `yaml:"password,omitempty" secret:"true"`
 }
 
 type oauth2 struct {
 	ClientID       string            `yaml:"client_id,omitempty"`
-	ClientSecret   string            `yaml:"client_secret,omitempty"`
+	ClientSecret   string            `yaml:"client_secret,omitempty" secret:"true"`
 	Scopes         []string          `yaml:"scopes,omitempty"`
 	TokenURL       string            `yaml:"token_url,omitempty"`
 	EndpointParams map[string]string `yaml:"endpoint_params,omitempty"`
 
 	TLSConfig *tlsConfig `yaml:"tls_config,omitempty"`
+
+	proxyConfig `yaml:",inline"`
 }
 
 type pagerdutyLink struct {
@@ -252,8 +281,8 @@ type emailConfig struct {
 	Hello         string            `yaml:"hello,omitempty" json:"hello,omitempty"`
 	Smarthost     config.HostPort   `yaml:"smarthost,omitempty" json:"smarthost,omitempty"`
 	AuthUsername  string            `yaml:"auth_username,omitempty" json:"auth_username,omitempty"`
-	AuthPassword  string            `yaml:"auth_password,omitempty" json:"auth_password,omitempty"`
-	AuthSecret    string            `yaml:"auth_secret,omitempty" json:"auth_secret,omitempty"`
+	AuthPassword  string            `yaml:"auth_password,omitempty" json:"auth_password,omitempty" secret:"true"`
+	AuthSecret    string            `yaml:"auth_secret,omitempty" json:"auth_secret,omitempty" secret:"true"`
 	AuthIdentity  string            `yaml:"auth_identity,omitempty" json:"auth_identity,omitempty"`
 	Headers       map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
 	HTML          string            `yaml:"html,omitempty" json:"html,omitempty"`
@@ -265,8 +294,8 @@ type emailConfig struct {
 type pushoverConfig struct {
 	VSendResolved *bool             `yaml:"send_resolved,omitempty" json:"send_resolved,omitempty"`
 	HTTPConfig    *httpClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
-	UserKey       string            `yaml:"user_key,omitempty" json:"user_key,omitempty"`
-	Token         string            `yaml:"token,omitempty" json:"token,omitempty"`
+	UserKey       string            `yaml:"user_key,omitempty" json:"user_key,omitempty" secret:"true"`
+	Token         string            `yaml:"token,omitempty" json:"token,omitempty" secret:"true"`
 	Title         string            `yaml:"title,omitempty" json:"title,omitempty"`
 	Message       string            `yaml:"message,omitempty" json:"message,omitempty"`
 	URL           string            `yaml:"url,omitempty" json:"url,omitempty"`
@@ -295,17 +324,93 @@ type telegramConfig struct {
 	VSendResolved        *bool             `yaml:"send_resolved,omitempty" json:"send_resolved,omitempty"`
 	HTTPConfig           *httpClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
 	APIUrl               string            `yaml:"api_url,omitempty" json:"api_url,omitempty"`
-	BotToken             string            `yaml:"bot_token,omitempty" json:"bot_token,omitempty"`
+	BotToken             string            `yaml:"bot_token,omitempty" json:"bot_token,omitempty" secret:"true"`
 	ChatID               int64             `yaml:"chat_id,omitempty" json:"chat_id,omitempty"`
 	Message              string            `yaml:"message,omitempty" json:"message,omitempty"`
 	DisableNotifications bool              `yaml:"disable_notifications,omitempty" json:"disable_notifications,omitempty"`
 	ParseMode            string            `yaml:"parse_mode,omitempty" json:"parse_mode,omitempty"`
 }
 
+type discordConfig struct {
+	VSendResolved *bool             `yaml:"send_resolved,omitempty" json:"send_resolved,omitempty"`
+	HTTPConfig    *httpClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+	WebhookURL    string            `yaml:"webhook_url,omitempty" json:"webhook_url,omitempty" secret:"true"`
+	Title         string            `yaml:"title,omitempty" json:"title,omitempty"`
+	Message       string            `yaml:"message,omitempty" json:"message,omitempty"`
+}
+
+type msTeamsConfig struct {
+	VSendResolved *bool             `yaml:"send_resolved,omitempty" json:"send_resolved,omitempty"`
+	HTTPConfig    *httpClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+	WebhookURL    string            `yaml:"webhook_url,omitempty" json:"webhook_url,omitempty" secret:"true"`
+	Title         string            `yaml:"title,omitempty" json:"title,omitempty"`
+	Summary       string            `yaml:"summary,omitempty" json:"summary,omitempty"`
+	Text          string            `yaml:"text,omitempty" json:"text,omitempty"`
+}
+
+type webexConfig struct {
+	VSendResolved *bool             `yaml:"send_resolved,omitempty" json:"send_resolved,omitempty"`
+	HTTPConfig    *httpClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+	APIURL        string            `yaml:"api_url,omitempty" json:"api_url,omitempty"`
+	RoomID        string            `yaml:"room_id,omitempty" json:"room_id,omitempty"`
+	Message       string            `yaml:"message,omitempty" json:"message,omitempty"`
+}
+
+type rocketchatConfig struct {
+	VSendResolved *bool              `yaml:"send_resolved,omitempty" json:"send_resolved,omitempty"`
+	HTTPConfig    *httpClientConfig  `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+	APIURL        string             `yaml:"api_url,omitempty" json:"api_url,omitempty"`
+	TokenID       string             `yaml:"token_id,omitempty" json:"token_id,omitempty" secret:"true"`
+	Token         string             `yaml:"token,omitempty" json:"token,omitempty" secret:"true"`
+	Channel       string             `yaml:"channel,omitempty" json:"channel,omitempty"`
+	Color         string             `yaml:"color,omitempty" json:"color,omitempty"`
+	Title         string             `yaml:"title,omitempty" json:"title,omitempty"`
+	TitleLink     string             `yaml:"title_link,omitempty" json:"title_link,omitempty"`
+	Text          string             `yaml:"text,omitempty" json:"text,omitempty"`
+	Fields        []rocketchatField  `yaml:"fields,omitempty" json:"fields,omitempty"`
+	ShortFields   bool               `yaml:"short_fields,omitempty" json:"short_fields,omitempty"`
+	Emoji         string             `yaml:"emoji,omitempty" json:"emoji,omitempty"`
+	IconURL       string             `yaml:"icon_url,omitempty" json:"icon_url,omitempty"`
+	ImageURL      string             `yaml:"image_url,omitempty" json:"image_url,omitempty"`
+	ThumbURL      string             `yaml:"thumb_url,omitempty" json:"thumb_url,omitempty"`
+	LinkNames     bool               `yaml:"link_names,omitempty" json:"link_names,omitempty"`
+	Actions       []rocketchatAction `yaml:"actions,omitempty" json:"actions,omitempty"`
+}
+
+type rocketchatField struct {
+	Title string `yaml:"title,omitempty" json:"title,omitempty"`
+	Value string `yaml:"value,omitempty" json:"value,omitempty"`
+	Short bool   `yaml:"short,omitempty" json:"short,omitempty"`
+}
+
+type rocketchatAction struct {
+	Type string `yaml:"type,omitempty" json:"type,omitempty"`
+	Text string `yaml:"text,omitempty" json:"text,omitempty"`
+	URL  string `yaml:"url,omitempty" json:"url,omitempty"`
+	Msg  string `yaml:"msg,omitempty" json:"msg,omitempty"`
+}
+
+type jiraConfig struct {
+	VSendResolved     *bool                  `yaml:"send_resolved,omitempty" json:"send_resolved,omitempty"`
+	HTTPConfig        *httpClientConfig      `yaml:"http_config,omitempty" json:"http_config,omitempty"`
+	APIURL            string                 `yaml:"api_url,omitempty" json:"api_url,omitempty"`
+	Project           string                 `yaml:"project,omitempty" json:"project,omitempty"`
+	IssueType         string                 `yaml:"issue_type,omitempty" json:"issue_type,omitempty"`
+	Summary           string                 `yaml:"summary,omitempty" json:"summary,omitempty"`
+	Description       string                 `yaml:"description,omitempty" json:"description,omitempty"`
+	Priority          string                 `yaml:"priority,omitempty" json:"priority,omitempty"`
+	Labels            []string               `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Fields            map[string]interface{} `yaml:"fields,omitempty" json:"fields,omitempty"`
+	ReopenTransition  string                 `yaml:"reopen_transition,omitempty" json:"reopen_transition,omitempty"`
+	ResolveTransition string                 `yaml:"resolve_transition,omitempty" json:"resolve_transition,omitempty"`
+	WontFixResolution string                 `yaml:"wont_fix_resolution,omitempty" json:"wont_fix_resolution,omitempty"`
+	ReopenDuration    string                 `yaml:"reopen_duration,omitempty" json:"reopen_duration,omitempty"`
+}
+
 type sigV4Config struct {
 	Region    string `yaml:"region,omitempty" json:"region,omitempty"`
 	AccessKey string `yaml:"access_key,omitempty" json:"access_key,omitempty"`
-	SecretKey string `yaml:"secret_key,omitempty" json:"secret_key,omitempty"`
+	SecretKey string `yaml:"secret_key,omitempty" json:"secret_key,omitempty" secret:"true"`
 	Profile   string `yaml:"profile,omitempty" json:"profile,omitempty"`
 	RoleARN   string `yaml:"role_arn,omitempty" json:"role_arn,omitempty"`
 }
@@ -313,7 +418,7 @@ type sigV4Config struct {
 type victorOpsConfig struct {
 	VSendResolved     *bool             `yaml:"send_resolved,omitempty" json:"send_resolved,omitempty"`
 	HTTPConfig        *httpClientConfig `yaml:"http_config,omitempty" json:"http_config,omitempty"`
-	APIKey            string            `yaml:"api_key,omitempty" json:"api_key,omitempty"`
+	APIKey            string            `yaml:"api_key,omitempty" json:"api_key,omitempty" secret:"true"`
 	APIURL            string            `yaml:"api_url,omitempty" json:"api_url,omitempty"`
 	RoutingKey        string            `yaml:"routing_key,omitempty" json:"routing_key,omitempty"`
 	MessageType       string            `yaml:"message_type,omitempty" json:"message_type,omitempty"`
@@ -323,4 +428,18 @@ type victorOpsConfig struct {
 	CustomFields      map[string]string `yaml:"custom_fields,omitempty" json:"custom_fields,omitempty"`
 }
 
-type muteTimeInterval config.MuteTimeInterval
+type muteTimeInterval struct {
+	Name          string         `yaml:"name" json:"name"`
+	TimeIntervals []timeInterval `yaml:"time_intervals" json:"time_intervals"`
+}
+
+// timeInterval is timeinterval.TimeInterval with the location of newer
+// alertmanager versions.
+type timeInterval struct {
+	Times       []timeinterval.TimeRange       `yaml:"times,omitempty" json:"times,omitempty"`
+	Weekdays    []timeinterval.WeekdayRange    `yaml:"weekdays,flow,omitempty" json:"weekdays,omitempty"`
+	DaysOfMonth []timeinterval.DayOfMonthRange `yaml:"days_of_month,flow,omitempty" json:"days_of_month,omitempty"`
+	Months      []timeinterval.MonthRange      `yaml:"months,flow,omitempty" json:"months,omitempty"`
+	Years       []timeinterval.YearRange       `yaml:"years,flow,omitempty" json:"years,omitempty"`
+	Location    string                         `yaml:"location,omitempty" json:"location,omitempty"`
+}